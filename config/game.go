@@ -19,10 +19,14 @@ type CnfResidence struct {
 	Interval  duration
 	Capacity  int     `validate:"gt=0"`
 	Randomize float64 `validate:"gte=0"`
+	// Demand is initial multiplier of Human generation. 0 means 1.
+	Demand float64 `validate:"gte=0"`
 }
 
 type CnfCompany struct {
 	Attract float64 `validate:"gt=0"`
+	// Demand is initial multiplier of commuters Company draws. 0 means 1.
+	Demand float64 `validate:"gte=0"`
 }
 
 // CnfGate is configuration about gate
@@ -47,7 +51,21 @@ type CnfTrain struct {
 
 // CnfHuman is configuration about human
 type CnfHuman struct {
-	Speed float64 `validate:"gt=0"`
+	Speed    float64  `validate:"gt=0"`
+	Lifespan duration `validate:"gt=0"`
+	// Work is how long Human works in Company before going home at outbound rate 1.
+	// 0 means Human never returns.
+	Work duration
+}
+
+// CnfDemand is configuration about daily demand profile
+type CnfDemand struct {
+	// Inbound is multiplier of Human generation from Residence to Company for each hour of day.
+	// Empty means flat demand.
+	Inbound []float64 `validate:"omitempty,len=24,dive,gte=0"`
+	// Outbound is multiplier of how fast Human finishes work in Company for each hour of day.
+	// Empty means Human leaves as soon as Work passes.
+	Outbound []float64 `validate:"omitempty,len=24,dive,gte=0"`
 }
 
 // CnfEntity is entity section of game.conf
@@ -60,6 +78,7 @@ type CnfEntity struct {
 	Platform  CnfPlatform
 	Train     CnfTrain
 	Human     CnfHuman
+	Demand    CnfDemand
 }
 
 // CnfProcedure is configuration about game proceding
@@ -69,16 +88,61 @@ type CnfProcedure struct {
 	Simulation bool
 }
 
+// CnfClock is configuration about simulated time
+type CnfClock struct {
+	// Step is simulated time advanced by one game step
+	Step duration
+	// Multiplier is ratio of simulated time to real time. 0 means pause.
+	Multiplier float64 `validate:"gte=0"`
+	// Day is simulated time of one in-game day
+	Day duration
+	// Start is time of day when game begins
+	Start duration
+	// Seed fixes random number generation for replay. 0 means random seed.
+	// Replay also requires routing to end at the same tick.
+	Seed int64
+}
+
 // CnfRouting is configuration about paralization
 type CnfRouting struct {
-	Worker int `validate:"gt=0"`
-	Alert  int `validate:"gte=0"`
+	// Worker is the number of goroutines shared by all route searches
+	Worker     int `validate:"gt=0"`
+	Alert      int `validate:"gte=0"`
+	Congestion CnfCongestion
+}
+
+// CnfCongestion is configuration about cost of route depending on congestion
+type CnfCongestion struct {
+	// Interval is period of recalculating cost. 0 means disabled.
+	Interval duration
+	// Crowd is penalty seconds for full Platform
+	Crowd float64 `validate:"gte=0"`
+	// Full is penalty seconds for full Trains
+	Full float64 `validate:"gte=0"`
+	// Wait is ratio of waiting time to headway of RailLine
+	Wait float64 `validate:"gte=0"`
 }
 
 // CnfBackup is configuration about backup interval
 type CnfBackup struct {
 	Enabled  bool
 	Interval duration
+	// Retry is how many times writing is retried after failure
+	Retry int `validate:"gte=0"`
+	// Wait is interval between retries
+	Wait duration
+}
+
+// CnfRanking is configuration about score snapshot
+type CnfRanking struct {
+	Interval  duration
+	Retention duration
+}
+
+// CnfGameMap is configuration about delta view of gamemap
+type CnfGameMap struct {
+	// Retention is how long removal of delegate is kept
+	Retention duration
 }
 
 // CnfPerf is configuration about performance logging
@@ -95,13 +159,33 @@ type CnfPerf struct {
 // CnfService is service section of game.conf
 type CnfService struct {
 	Procedure CnfProcedure
+	Clock     CnfClock
 	Routing   CnfRouting
 	Backup    CnfBackup
+	Ranking   CnfRanking
+	GameMap   CnfGameMap
 	Perf      CnfPerf
 }
 
+// CnfEconomy is configuration about money
+type CnfEconomy struct {
+	// Initial is money which new Player has
+	Initial int64 `validate:"gte=0"`
+	// Rail is cost per one second of RailEdge.Cost
+	Rail float64 `validate:"gte=0"`
+	// Station is cost per one Station
+	Station int64 `validate:"gte=0"`
+	// Train is cost per one Train
+	Train int64 `validate:"gte=0"`
+	// BaseFare is revenue per one passenger
+	BaseFare int64 `toml:"base_fare" validate:"gte=0"`
+	// DistFare is revenue per one meter passenger rides
+	DistFare float64 `toml:"dist_fare" validate:"gte=0"`
+}
+
 // CnfGame is root element of game.conf
 type CnfGame struct {
 	Entity  CnfEntity
 	Service CnfService
+	Economy CnfEconomy
 }
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"log"
+	"reflect"
 
 	"github.com/BurntSushi/toml"
 	"gopkg.in/go-playground/validator.v9"
@@ -25,10 +26,19 @@ func Load(confDir string) (*Config, error) {
 		if _, err := toml.DecodeFile(fmt.Sprintf("%s/%s", confDir, file), v); err != nil {
 			return &config, fmt.Errorf("failed to load conf: %v", err)
 		}
-		if err := validator.New().Struct(v); err != nil {
+		if err := newValidator().Struct(v); err != nil {
 			return &config, fmt.Errorf("%+v, %v", v, err)
 		}
 	}
 	log.Println("config file was successfully loaded.")
 	return &config, nil
 }
+
+// newValidator returns validator which checks duration as time.Duration
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterCustomTypeFunc(func(f reflect.Value) interface{} {
+		return f.Interface().(duration).D
+	}, duration{})
+	return v
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 )
 
 func TestLoad(t *testing.T) {
@@ -14,3 +15,43 @@ func TestLoad(t *testing.T) {
 		t.Errorf("TestLoad() got %v, want nil", err)
 	}
 }
+
+func TestValidateLifespan(t *testing.T) {
+	for _, c := range []struct {
+		in   time.Duration
+		want bool
+	}{
+		{time.Minute, true},
+		{0, false},
+		{-time.Minute, false},
+	} {
+		t.Run(c.in.String(), func(t *testing.T) {
+			human := CnfHuman{Speed: 1, Lifespan: duration{c.in}}
+			if got := newValidator().Struct(human) == nil; got != c.want {
+				t.Errorf("Struct(Lifespan=%v) valid=%v, want %v", c.in, got, c.want)
+			}
+		})
+	}
+}
+
+func TestValidateDemand(t *testing.T) {
+	flat := make([]float64, 24)
+	negative := make([]float64, 24)
+	negative[18] = -1
+	for _, c := range []struct {
+		name string
+		in   CnfDemand
+		want bool
+	}{
+		{"empty", CnfDemand{}, true},
+		{"hourly", CnfDemand{Inbound: flat, Outbound: flat}, true},
+		{"short", CnfDemand{Outbound: flat[:23]}, false},
+		{"negative", CnfDemand{Outbound: negative}, false},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			if got := newValidator().Struct(c.in) == nil; got != c.want {
+				t.Errorf("Struct(%+v) valid=%v, want %v", c.in, got, c.want)
+			}
+		})
+	}
+}
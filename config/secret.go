@@ -8,8 +8,10 @@ type CnfAdmin struct {
 
 // CnfDatabase is configuration about connectiing user data stored database
 type CnfDatabase struct {
-	Driver string `validation:"oneof=mysql"`
-	Spec   string
+	// Driver is "mysql" or "sqlite3"
+	Driver string `validate:"oneof=mysql sqlite3"`
+	// Spec is DSN for mysql or file path for sqlite3
+	Spec string
 }
 
 // CnfTwitter is configuration about Twitter OAuth
@@ -0,0 +1,20 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/yasshi2525/RushHour/services"
+)
+
+// BackupStats returns metrics of backup
+// @Description the number of succeeded, failed and retried backups and records waiting for next backup
+// @Tags services.BackupStats
+// @Summary backup metrics
+// @Produce json
+// @Success 200 {object} services.BackupStats "backup metrics"
+// @Failure 401 {object} errInfo "invalid jwt"
+// @Router /backup [get]
+func BackupStats(c *gin.Context) {
+	stats := services.GetBackupStats()
+	c.Set(keyOk, &stats)
+}
@@ -0,0 +1,80 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/yasshi2525/RushHour/entities"
+	"github.com/yasshi2525/RushHour/services"
+)
+
+type residenceDemandRequest struct {
+	Residence uint `form:"rid" json:"rid" validate:"required,numeric"`
+	// Demand is multiplier of Human generation
+	Demand *float64 `form:"demand" json:"demand" validate:"required,gte=0"`
+}
+
+type residenceDemandResponse struct {
+	Residence *entities.Residence `json:"r"`
+}
+
+// ChangeResidenceDemand returns result of changing demand of residence
+// @Description result of changing multiplier of Human generation of residence
+// @Tags residenceDemandResponse
+// @Summary change demand of residence
+// @Accept json
+// @Produce json
+// @Param rid body integer true "residence id"
+// @Param demand body number true "multiplier of Human generation"
+// @Success 200 {object} residenceDemandResponse "changed residence"
+// @Failure 400 {object} errInfo "reason of fail"
+// @Failure 401 {object} errInfo "invalid jwt"
+// @Router /residences/demand [post]
+func ChangeResidenceDemand(c *gin.Context) {
+	o := c.MustGet(keyOwner).(*entities.Player)
+	params := residenceDemandRequest{}
+	if err := c.ShouldBind(&params); err != nil {
+		c.Set(keyErr, err)
+	} else if r, err := validateEntity(entities.RESIDENCE, params.Residence); err != nil {
+		c.Set(keyErr, err)
+	} else if err := services.ChangeResidenceDemand(o, r.(*entities.Residence), *params.Demand); err != nil {
+		c.Set(keyErr, err)
+	} else {
+		c.Set(keyOk, &residenceDemandResponse{r.(*entities.Residence)})
+	}
+}
+
+type companyDemandRequest struct {
+	Company uint `form:"cid" json:"cid" validate:"required,numeric"`
+	// Demand is multiplier of commuters company draws
+	Demand *float64 `form:"demand" json:"demand" validate:"required,gte=0"`
+}
+
+type companyDemandResponse struct {
+	Company *entities.Company `json:"c"`
+}
+
+// ChangeCompanyDemand returns result of changing demand of company
+// @Description result of changing multiplier of commuters company draws
+// @Tags companyDemandResponse
+// @Summary change demand of company
+// @Accept json
+// @Produce json
+// @Param cid body integer true "company id"
+// @Param demand body number true "multiplier of commuters"
+// @Success 200 {object} companyDemandResponse "changed company"
+// @Failure 400 {object} errInfo "reason of fail"
+// @Failure 401 {object} errInfo "invalid jwt"
+// @Router /companies/demand [post]
+func ChangeCompanyDemand(c *gin.Context) {
+	o := c.MustGet(keyOwner).(*entities.Player)
+	params := companyDemandRequest{}
+	if err := c.ShouldBind(&params); err != nil {
+		c.Set(keyErr, err)
+	} else if cp, err := validateEntity(entities.COMPANY, params.Company); err != nil {
+		c.Set(keyErr, err)
+	} else if err := services.ChangeCompanyDemand(o, cp.(*entities.Company), *params.Demand); err != nil {
+		c.Set(keyErr, err)
+	} else {
+		c.Set(keyOk, &companyDemandResponse{cp.(*entities.Company)})
+	}
+}
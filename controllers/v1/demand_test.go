@@ -0,0 +1,70 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yasshi2525/RushHour/services"
+)
+
+func TestValidDemandRequest(t *testing.T) {
+	v := initValidate()
+	zero, negative := 0.0, -1.0
+	cases := []struct {
+		in   interface{}
+		want []string
+	}{
+		{
+			in:   residenceDemandRequest{Residence: 1, Demand: &zero},
+			want: nil,
+		}, {
+			// no demand
+			in:   residenceDemandRequest{Residence: 1},
+			want: []string{"Key: 'residenceDemandRequest.demand' Error:Field validation for 'demand' failed on the 'required' tag"},
+		}, {
+			// negative demand
+			in:   companyDemandRequest{Company: 1, Demand: &negative},
+			want: []string{"Key: 'companyDemandRequest.demand' Error:Field validation for 'demand' failed on the 'gte' tag"},
+		},
+	}
+
+	for _, c := range cases {
+		assertValidation("validDemandRequest", t, v, c.in, c.want)
+	}
+}
+
+func TestChangeDemand(t *testing.T) {
+	services.MuModel.Lock()
+	admin, _ := services.PasswordSignIn(conf.Secret.Admin.UserName, conf.Secret.Admin.Password)
+	r, _ := services.CreateResidence(admin, 1, 1)
+	c, _ := services.CreateCompany(admin, 2, 2)
+	services.MuModel.Unlock()
+	token, _ := auther.BuildJWT(admin.ExportJWTInfo())
+
+	cases := []struct {
+		path    string
+		handler gin.HandlerFunc
+		in      gin.H
+		got     func() float64
+	}{
+		{"/residences/demand", ChangeResidenceDemand, gin.H{"rid": r.ID, "demand": 2.5}, func() float64 { return r.Demand }},
+		{"/companies/demand", ChangeCompanyDemand, gin.H{"cid": c.ID, "demand": 0.5}, func() float64 { return c.Demand }},
+	}
+	for _, tc := range cases {
+		w, _, router := prepare(JWTHandler(), AdminHandler(), ModelHandler())
+		router.POST(tc.path, tc.handler)
+		assertOkResponse(t, paramAssertOk{
+			Method: "POST",
+			Path:   tc.path,
+			Jwt:    token,
+			R:      router,
+			W:      w,
+			In:     tc.in,
+			Assert: func(got map[string]interface{}) {},
+		})
+		if got, want := tc.got(), tc.in["demand"].(float64); got != want {
+			t.Errorf("%s demand got %f, want %f", tc.path, got, want)
+		}
+	}
+}
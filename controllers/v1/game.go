@@ -1,6 +1,8 @@
 package v1
 
 import (
+	"net/http"
+
 	"github.com/gin-gonic/gin"
 
 	"github.com/yasshi2525/RushHour/entities"
@@ -9,17 +11,27 @@ import (
 
 type gameStatus struct {
 	Status bool `json:"status"`
+	// Clock is simulated time and in-game time of day
+	Clock *services.ClockStatus `json:"clock"`
+	// Routing is progress of routing
+	Routing *services.RoutingStatus `json:"routing"`
+}
+
+func newGameStatus() *gameStatus {
+	return &gameStatus{services.IsInOperation(), services.GameClock.Status(), services.GetRoutingStatus()}
 }
 
 // GameStatus returns game status
-// @Description game status
+// It reads published view, so it must not be called after ModelHandler.
+// @Description game status, in-game time of day and progress of routing
 // @Tags gameStatus
 // @Summary game status
 // @Produce json
 // @Success 200 {object} gameStatus "game status"
 // @Router /game [get]
 func GameStatus(c *gin.Context) {
-	c.Set(keyOk, &gameStatus{services.IsInOperation()})
+	v := services.CurrentView()
+	c.JSON(http.StatusOK, &gameStatus{services.IsInOperation(), v.Clock, v.Routing})
 }
 
 // StartGame returns result of game starting
@@ -35,7 +47,7 @@ func StartGame(c *gin.Context) {
 	if !services.IsInOperation() {
 		services.Start()
 	}
-	c.Set(keyOk, &gameStatus{services.IsInOperation()})
+	c.Set(keyOk, newGameStatus())
 }
 
 // StopGame returns result of game stopping
@@ -49,9 +61,39 @@ func StartGame(c *gin.Context) {
 // @Router /game/stop [post]
 func StopGame(c *gin.Context) {
 	if services.IsInOperation() {
-		services.Stop()
+		if err := services.Stop(); err != nil {
+			c.Set(keyErr, err)
+			return
+		}
+	}
+	c.Set(keyOk, newGameStatus())
+}
+
+type speedRequest struct {
+	// Multiplier is ratio of simulated time to real time. 0 means pause.
+	Multiplier *float64 `form:"multiplier" json:"multiplier" validate:"required,gte=0,lte=16"`
+}
+
+// ChangeSpeed returns result of changing simulation speed
+// @Description result of changing simulation speed
+// @Tags gameStatus
+// @Summary change speed
+// @Accept json
+// @Produce json
+// @Param multiplier body number true "ratio of simulated time to real time (0 means pause)"
+// @Success 200 {object} gameStatus "game status"
+// @Failure 400 {object} errInfo "reason of fail"
+// @Failure 401 {object} errInfo "invalid jwt"
+// @Router /game/speed [post]
+func ChangeSpeed(c *gin.Context) {
+	params := speedRequest{}
+	if err := c.ShouldBind(&params); err != nil {
+		c.Set(keyErr, err)
+	} else if err := services.SetMultiplier(*params.Multiplier); err != nil {
+		c.Set(keyErr, err)
+	} else {
+		c.Set(keyOk, newGameStatus())
 	}
-	c.Set(keyOk, &gameStatus{services.IsInOperation()})
 }
 
 type purgeStatus struct {
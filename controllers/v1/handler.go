@@ -70,40 +70,69 @@ func AdminHandler() gin.HandlerFunc {
 // ModelHandler handles model and error controling
 // It causes panic when neither result keyOk or keyErr is set
 // It should be called after JWTHandeler and AdminHandler are called
+// Read-only requests share lock of model and others lock it exclusively.
+// So handler of GET request must not change model.
+// View for lock-free readers is published after other requests.
 func ModelHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		services.MuModel.Lock()
-		defer services.MuModel.Unlock()
+		if isReadOnly(c.Request.Method) {
+			services.MuModel.RLock()
+			defer services.MuModel.RUnlock()
+		} else {
+			services.MuModel.Lock()
+			defer services.MuModel.Unlock()
+			defer services.PublishView()
+		}
 		c.Next()
-		// error reported
-		if res, has := c.Get(keyErr); has {
-			// error caused by validation
-			if verr, ok := res.(validator.ValidationErrors); ok {
-				c.JSON(http.StatusBadRequest, buildErrorMessages(verr))
-			} else {
-				// error caused by services
-				if e, ok := res.(error); ok {
-					// single reason
-					c.JSON(http.StatusBadRequest, &errInfo{Err: []string{e.Error()}})
-				} else if es, ok := res.([]error); ok {
-					// multiple reason
-					var msgs []string
-					for _, e := range es {
-						msgs = append(msgs, e.Error())
-					}
-					c.JSON(http.StatusBadRequest, &errInfo{Err: msgs})
-				} else {
-					// unhandle error
-					c.JSON(http.StatusBadRequest, &errInfo{Err: []string{fmt.Sprintf("%v", e)}})
-				}
+		respond(c)
+	}
+}
 
-			}
+// ViewHandler handles error controling for read-only requests without lock of model
+// It causes panic when neither result keyOk or keyErr is set
+// Handler of it must read published view or route instead of model.
+func ViewHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		respond(c)
+	}
+}
+
+// respond writes result keyOk or keyErr
+func respond(c *gin.Context) {
+	// error reported
+	if res, has := c.Get(keyErr); has {
+		// error caused by validation
+		if verr, ok := res.(validator.ValidationErrors); ok {
+			c.JSON(http.StatusBadRequest, buildErrorMessages(verr))
 		} else {
-			c.JSON(http.StatusOK, c.MustGet(keyOk))
+			// error caused by services
+			if e, ok := res.(error); ok {
+				// single reason
+				c.JSON(http.StatusBadRequest, &errInfo{Err: []string{e.Error()}})
+			} else if es, ok := res.([]error); ok {
+				// multiple reason
+				var msgs []string
+				for _, e := range es {
+					msgs = append(msgs, e.Error())
+				}
+				c.JSON(http.StatusBadRequest, &errInfo{Err: msgs})
+			} else {
+				// unhandle error
+				c.JSON(http.StatusBadRequest, &errInfo{Err: []string{fmt.Sprintf("%v", e)}})
+			}
+
 		}
+	} else {
+		c.JSON(http.StatusOK, c.MustGet(keyOk))
 	}
 }
 
+// isReadOnly returns whether request of specified method doesn't change model or not.
+func isReadOnly(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
+
 func parseJWT(header string) (*entities.Player, error) {
 	url := conf.Secret.Auth.BaseURL
 	token := strings.TrimPrefix(header, "Bearer ")
@@ -118,6 +147,8 @@ func parseJWT(header string) (*entities.Player, error) {
 
 	data := obj.Claims.(jwt.MapClaims)
 	value := data[fmt.Sprintf("%s/id", url)]
+	services.MuModel.RLock()
+	defer services.MuModel.RUnlock()
 	o, ok := services.Model.Players[uint(value.(float64))]
 	if !ok {
 		return nil, fmt.Errorf("specified user is already removed")
@@ -0,0 +1,92 @@
+package v1
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yasshi2525/RushHour/services"
+)
+
+func TestModelHandler(t *testing.T) {
+	// serve returns whether request ends while other reader holds lock of model
+	serve := func(method string) bool {
+		w, _, r := prepare(ModelHandler())
+		r.Handle(method, "/", func(c *gin.Context) {
+			c.Set(keyOk, gin.H{})
+		})
+		services.MuModel.RLock()
+		defer services.MuModel.RUnlock()
+
+		done := make(chan bool)
+		go func() {
+			req, _ := http.NewRequest(method, "/", nil)
+			r.ServeHTTP(w, req)
+			close(done)
+		}()
+		select {
+		case <-done:
+			return true
+		case <-time.After(100 * time.Millisecond):
+			// let request finish after releasing lock
+			go func() { <-done }()
+			return false
+		}
+	}
+
+	if !serve(http.MethodGet) {
+		t.Errorf("GET got blocked by reader, want shared lock")
+	}
+	if serve(http.MethodPost) {
+		t.Errorf("POST got served with reader, want exclusive lock")
+	}
+}
+
+func TestPublishedView(t *testing.T) {
+	services.MuModel.Lock()
+	services.PublishView()
+	services.MuModel.Unlock()
+
+	cases := []struct {
+		path    string
+		query   string
+		handler gin.HandlerFunc
+		view    bool
+		want    int
+	}{
+		{"/players", "", Players, false, http.StatusOK},
+		{"/game", "", GameStatus, false, http.StatusOK},
+		{"/gamemap", "?x=0&y=0&scale=16&delegate=0", GameMap, true, http.StatusOK},
+		{"/ranking", "", Ranking, true, http.StatusOK},
+		// route is never applied because game procedure doesn't run
+		{"/journey", "?from=1&to=1", Journey, true, http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		w, _, r := prepare()
+		if c.view {
+			w, _, r = prepare(ViewHandler())
+		}
+		r.GET(c.path, c.handler)
+
+		// simulation holds exclusive lock
+		services.MuModel.Lock()
+		done := make(chan bool)
+		go func() {
+			req, _ := http.NewRequest(http.MethodGet, c.path+c.query, nil)
+			r.ServeHTTP(w, req)
+			close(done)
+		}()
+		select {
+		case <-done:
+			if w.Code != c.want {
+				t.Errorf("%s got %d, want %d", c.path, w.Code, c.want)
+			}
+		case <-time.After(100 * time.Millisecond):
+			t.Errorf("%s got blocked by writer, want published view", c.path)
+		}
+		services.MuModel.Unlock()
+		<-done
+	}
+}
@@ -0,0 +1,49 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/yasshi2525/RushHour/services"
+)
+
+// journeyRequest represents requirement to plan commute from Residence to Company
+type journeyRequest struct {
+	// From is id of Residence
+	From uint `form:"from" json:"from" validate:"required,numeric"`
+	// To is id of Company
+	To uint `form:"to" json:"to" validate:"required,numeric"`
+	// K is the number of alternatives in addition to the best journey
+	K int `form:"k" json:"k" validate:"gte=0,lte=5"`
+	// Sort is criterion which journeys are sorted by
+	Sort string `form:"sort" json:"sort" validate:"omitempty,oneof=time transfers fare"`
+}
+
+type journeyResponse struct {
+	Journeys []*services.Journey `json:"journeys"`
+}
+
+// Journey returns the best commute from Residence to Company and its alternatives
+// It reads route without lock of model, so it must be called after ViewHandler instead of ModelHandler.
+// @Description the best commute from residence to company on current route and its alternatives with time, transfers and fare
+// @Tags journeyResponse
+// @Summary plan journey
+// @Accept json
+// @Produce json
+// @Param from query integer true "residence id"
+// @Param to query integer true "company id"
+// @Param k query integer false "the number of alternatives (0-5)"
+// @Param sort query string false "sort key (time, transfers, fare)"
+// @Success 200 {object} journeyResponse "journeys"
+// @Failure 400 {object} errInfo "reason of fail"
+// @Failure 503 {object} errInfo "under maintenance"
+// @Router /journey [get]
+func Journey(c *gin.Context) {
+	params := journeyRequest{}
+	if err := c.ShouldBind(&params); err != nil {
+		c.Set(keyErr, err)
+	} else if js, err := services.FindJourneys(params.From, params.To, params.K, params.Sort); err != nil {
+		c.Set(keyErr, err)
+	} else {
+		c.Set(keyOk, &journeyResponse{js})
+	}
+}
@@ -0,0 +1,44 @@
+package v1
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestValidJourneyRequest(t *testing.T) {
+	v := initValidate()
+	cases := []struct {
+		in   journeyRequest
+		want []string
+	}{
+		{
+			in:   journeyRequest{From: 1, To: 1, K: 5, Sort: "fare"},
+			want: nil,
+		}, {
+			// no origin
+			in:   journeyRequest{To: 1},
+			want: []string{"Key: 'journeyRequest.from' Error:Field validation for 'from' failed on the 'required' tag"},
+		}, {
+			// too many alternatives
+			in:   journeyRequest{From: 1, To: 1, K: 6},
+			want: []string{"Key: 'journeyRequest.k' Error:Field validation for 'k' failed on the 'lte' tag"},
+		}, {
+			// unknown sort key
+			in:   journeyRequest{From: 1, To: 1, Sort: "distance"},
+			want: []string{"Key: 'journeyRequest.sort' Error:Field validation for 'sort' failed on the 'oneof' tag"},
+		},
+	}
+
+	for _, c := range cases {
+		assertValidation("validJourneyRequest", t, v, c.in, c.want)
+	}
+}
+
+func TestJourney(t *testing.T) {
+	w, _, r := prepare(ViewHandler())
+	r.GET("/journey", Journey)
+	req, _ := http.NewRequest("GET", "/journey?from=100000&to=1", nil)
+	r.ServeHTTP(w, req)
+	// route is never applied because game procedure doesn't run
+	assertErrorResponse("/journey", t, w, []string{"route is not searched yet"})
+}
@@ -20,6 +20,8 @@ type gameMapRequest struct {
 	Scale string `form:"scale" json:"scale" validate:"required,numeric"`
 	// Delegate is 2^Delegate grid of map
 	Delegate string `form:"delegate" json:"delegate" validate:"required,numeric"`
+	// Since is unix time. Only delegates changed after it are returned when specified
+	Since string `form:"since" json:"since" validate:"omitempty,numeric"`
 }
 
 // export converts string to float64
@@ -31,6 +33,12 @@ func (v *gameMapRequest) export() (int, int, int, int) {
 	return int(x), int(y), int(sc), int(dlg)
 }
 
+// since converts string to unix time
+func (v *gameMapRequest) since() int64 {
+	since, _ := strconv.ParseInt(v.Since, 10, 64)
+	return since
+}
+
 // validGameMapRequest validates that GameMapRequest contains game whole map
 func validGameMapRequest(sl validator.StructLevel) {
 	v := sl.Current().Interface().(gameMapRequest)
@@ -80,6 +88,7 @@ func validGameMapRequest(sl validator.StructLevel) {
 }
 
 // GameMap returns all data of gamemap
+// It reads published view, so it must be called after ViewHandler instead of ModelHandler.
 // @Description entities are delegate object
 // @Tags entities.DelegateMap
 // @Summary get all entities in specified area
@@ -89,6 +98,7 @@ func validGameMapRequest(sl validator.StructLevel) {
 // @Param cy query number true "y coordinate"
 // @Param scale query number true "width,height(100%)=2^scale"
 // @Param delegate query number true "width,height(grid)=2^delegate"
+// @Param since query number false "unix time. only entities changed after it and id of removed ones are returned (delta=true)"
 // @Success 200 {object} entities.DelegateMap "map centered (x,y) with grid in (width,height)"
 // @Failure 400 {array} string "reasons of error when cx, cy and scale are out of area"
 // @Router /gamemap [get]
@@ -96,6 +106,9 @@ func GameMap(c *gin.Context) {
 	params := gameMapRequest{}
 	if err := c.ShouldBind(&params); err != nil {
 		c.Set(keyErr, err)
+	} else if params.Since != "" {
+		x, y, sc, dlg := params.export()
+		c.Set(keyOk, services.ViewDelegateMapSince(x, y, sc, dlg, params.since()))
 	} else {
 		c.Set(keyOk, services.ViewDelegateMap(params.export()))
 	}
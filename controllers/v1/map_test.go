@@ -108,6 +108,16 @@ func TestValidgameMapRequest(t *testing.T) {
 				"Key: 'gameMapRequest.scale' Error:Field validation for 'scale' failed on the 'numeric' tag",
 				"Key: 'gameMapRequest.delegate' Error:Field validation for 'delegate' failed on the 'numeric' tag",
 			},
+		}, {
+			// invalid since
+			in: gameMapRequest{
+				X:        "0",
+				Y:        "0",
+				Scale:    fmt.Sprintf("%d", conf.Game.Entity.MinScale),
+				Delegate: "0",
+				Since:    "invalid",
+			},
+			want: []string{"Key: 'gameMapRequest.since' Error:Field validation for 'since' failed on the 'numeric' tag"},
 		}, {
 			// empty
 			in: gameMapRequest{},
@@ -161,7 +171,7 @@ func TestGameMap(t *testing.T) {
 			},
 		}
 		for _, c := range cases {
-			w, _, r := prepare(ModelHandler())
+			w, _, r := prepare(ViewHandler())
 			r.GET("/gamemap", GameMap)
 			assertOkResponse(t, paramAssertOk{
 				Method: "GET",
@@ -291,7 +301,7 @@ func TestGameMap(t *testing.T) {
 			},
 		}
 		for _, c := range cases {
-			w, _, r := prepare(ModelHandler())
+			w, _, r := prepare(ViewHandler())
 			r.GET("/gamemap", GameMap)
 			url := fmt.Sprintf("/gamemap?x=%s&y=%s&scale=%s&delegate=%s", c.in.X, c.in.Y, c.in.Scale, c.in.Delegate)
 			req, _ := http.NewRequest("GET", url, nil)
@@ -1,17 +1,20 @@
 package v1
 
 import (
+	"encoding/json"
+	"net/http"
+
 	"github.com/gin-gonic/gin"
 
-	"github.com/yasshi2525/RushHour/entities"
 	"github.com/yasshi2525/RushHour/services"
 )
 
 type players struct {
-	Contents map[uint]*entities.Player `json:"players"`
+	Contents json.RawMessage `json:"players"`
 }
 
 // Players returns list of player
+// It reads published view, so it must not be called after ModelHandler.
 // @Description list of player
 // @Tags []entities.Player
 // @Summary list of player
@@ -21,5 +24,5 @@ type players struct {
 // @Failure 503 {object} errInfo "under maintenance"
 // @Router /players [get]
 func Players(c *gin.Context) {
-	c.Set(keyOk, &players{services.Model.Players})
+	c.JSON(http.StatusOK, &players{services.CurrentView().Players})
 }
@@ -0,0 +1,208 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+
+	"github.com/yasshi2525/RushHour/entities"
+	"github.com/yasshi2525/RushHour/services"
+)
+
+type railLineRequest struct {
+	Name string `form:"name" json:"name" validate:"omitempty,max=64"`
+	// AutoExt makes RailLine extend along rail automatically
+	AutoExt bool `form:"ext" json:"ext"`
+	// AutoPass makes Train pass through Station
+	AutoPass bool `form:"pass" json:"pass"`
+}
+
+type railLineResponse struct {
+	RailLine *entities.RailLine `json:"l"`
+}
+
+// CreateRailLine returns result of rail line creation
+// @Description result of rail line creation
+// @Tags railLineResponse
+// @Summary create rail line
+// @Accept json
+// @Produce json
+// @Param name body string false "rail line name"
+// @Param ext body boolean false "extend along rail automatically"
+// @Param pass body boolean false "pass through station"
+// @Success 200 {object} railLineResponse "created rail line"
+// @Failure 400 {object} errInfo "reason of fail"
+// @Failure 401 {object} errInfo "invalid jwt"
+// @Failure 503 {object} errInfo "under maintenance"
+// @Router /rail_lines [post]
+func CreateRailLine(c *gin.Context) {
+	o := c.MustGet(keyOwner).(*entities.Player)
+	params := railLineRequest{}
+	if err := c.ShouldBind(&params); err != nil {
+		c.Set(keyErr, err)
+	} else if l, err := services.CreateRailLine(o, params.Name, params.AutoExt, params.AutoPass); err != nil {
+		c.Set(keyErr, err)
+	} else {
+		c.Set(keyOk, &railLineResponse{l})
+	}
+}
+
+type startRailLineRequest struct {
+	RailLine uint `form:"lid" json:"lid" validate:"required,numeric"`
+	Platform uint `form:"pid" json:"pid" validate:"required,numeric"`
+}
+
+type lineTasksResponse struct {
+	RailLine  uint                         `json:"lid"`
+	LineTasks []*entities.DelegateLineTask `json:"lts"`
+}
+
+// StartRailLine returns result of rail line departure
+// @Description result of rail line departure from platform
+// @Tags lineTasksResponse
+// @Summary start rail line
+// @Accept json
+// @Produce json
+// @Param scale body number true "width,height(100%)=2^scale"
+// @Param lid body integer true "rail line id"
+// @Param pid body integer true "platform id"
+// @Success 200 {object} lineTasksResponse "line tasks of rail line"
+// @Failure 400 {object} errInfo "reason of fail"
+// @Failure 401 {object} errInfo "invalid jwt"
+// @Failure 503 {object} errInfo "under maintenance"
+// @Router /rail_lines/start [post]
+func StartRailLine(c *gin.Context) {
+	o := c.MustGet(keyOwner).(*entities.Player)
+	sc := scaleRequest{}
+	if err := c.ShouldBindBodyWith(&sc, binding.JSON); err != nil {
+		c.Set(keyErr, err)
+	} else {
+		params := startRailLineRequest{}
+		if err := c.ShouldBindBodyWith(&params, binding.JSON); err != nil {
+			c.Set(keyErr, err)
+		} else if l, err := validateEntity(entities.RAILLINE, params.RailLine); err != nil {
+			c.Set(keyErr, err)
+		} else if p, err := validateEntity(entities.PLATFORM, params.Platform); err != nil {
+			c.Set(keyErr, err)
+		} else if err := services.StartRailLine(o, l.(*entities.RailLine), p.(*entities.Platform)); err != nil {
+			c.Set(keyErr, err)
+		} else {
+			setLineTasksResponse(c, l.(*entities.RailLine), sc.Scale)
+		}
+	}
+}
+
+type insertRailLineRequest struct {
+	RailLine uint `form:"lid" json:"lid" validate:"required,numeric"`
+	RailEdge uint `form:"reid" json:"reid" validate:"required,numeric"`
+}
+
+// InsertRailLine returns result of rail line extension
+// @Description result of rail line extension over rail edge
+// @Tags lineTasksResponse
+// @Summary insert rail edge to rail line
+// @Accept json
+// @Produce json
+// @Param scale body number true "width,height(100%)=2^scale"
+// @Param lid body integer true "rail line id"
+// @Param reid body integer true "rail edge id"
+// @Success 200 {object} lineTasksResponse "line tasks of rail line"
+// @Failure 400 {object} errInfo "reason of fail"
+// @Failure 401 {object} errInfo "invalid jwt"
+// @Failure 503 {object} errInfo "under maintenance"
+// @Router /rail_lines/insert [post]
+func InsertRailLine(c *gin.Context) {
+	o := c.MustGet(keyOwner).(*entities.Player)
+	sc := scaleRequest{}
+	if err := c.ShouldBindBodyWith(&sc, binding.JSON); err != nil {
+		c.Set(keyErr, err)
+	} else {
+		params := insertRailLineRequest{}
+		if err := c.ShouldBindBodyWith(&params, binding.JSON); err != nil {
+			c.Set(keyErr, err)
+		} else if l, err := validateEntity(entities.RAILLINE, params.RailLine); err != nil {
+			c.Set(keyErr, err)
+		} else if re, err := validateEntity(entities.RAILEDGE, params.RailEdge); err != nil {
+			c.Set(keyErr, err)
+		} else if err := services.InsertLineTaskRailEdge(o, l.(*entities.RailLine), re.(*entities.RailEdge)); err != nil {
+			c.Set(keyErr, err)
+		} else {
+			setLineTasksResponse(c, l.(*entities.RailLine), sc.Scale)
+		}
+	}
+}
+
+type ringRailLineRequest struct {
+	RailLine uint `form:"lid" json:"lid" validate:"required,numeric"`
+}
+
+type ringRailLineResponse struct {
+	lineTasksResponse
+	// Ringed is false when RailLine cannot be ringed
+	Ringed bool `json:"ringed"`
+}
+
+// RingRailLine returns result of rail line ringing
+// @Description result of connecting tail and head of rail line
+// @Tags ringRailLineResponse
+// @Summary ring rail line
+// @Accept json
+// @Produce json
+// @Param scale body number true "width,height(100%)=2^scale"
+// @Param lid body integer true "rail line id"
+// @Success 200 {object} ringRailLineResponse "line tasks of rail line"
+// @Failure 400 {object} errInfo "reason of fail"
+// @Failure 401 {object} errInfo "invalid jwt"
+// @Failure 503 {object} errInfo "under maintenance"
+// @Router /rail_lines/ring [post]
+func RingRailLine(c *gin.Context) {
+	o := c.MustGet(keyOwner).(*entities.Player)
+	sc := scaleRequest{}
+	if err := c.ShouldBindBodyWith(&sc, binding.JSON); err != nil {
+		c.Set(keyErr, err)
+	} else {
+		params := ringRailLineRequest{}
+		if err := c.ShouldBindBodyWith(&params, binding.JSON); err != nil {
+			c.Set(keyErr, err)
+		} else if l, err := validateEntity(entities.RAILLINE, params.RailLine); err != nil {
+			c.Set(keyErr, err)
+		} else if ringed, err := services.RingRailLine(o, l.(*entities.RailLine)); err != nil {
+			c.Set(keyErr, err)
+		} else if lts, err := services.FindDelegateLineTasks(l.(*entities.RailLine), sc.Scale); err != nil {
+			c.Set(keyErr, err)
+		} else {
+			c.Set(keyOk, &ringRailLineResponse{lineTasksResponse{l.B().ID, lts}, ringed})
+		}
+	}
+}
+
+func setLineTasksResponse(c *gin.Context, l *entities.RailLine, scale int) {
+	if lts, err := services.FindDelegateLineTasks(l, scale); err != nil {
+		c.Set(keyErr, err)
+	} else {
+		c.Set(keyOk, &lineTasksResponse{l.ID, lts})
+	}
+}
+
+// RemoveRailLine returns result of rail line deletion
+// @Description result of rail line deletion
+// @Tags removeResponse
+// @Summary remove rail line
+// @Accept json
+// @Produce json
+// @Param id body integer true "rail line id"
+// @Success 200 {object} removeResponse "removed rail line"
+// @Failure 400 {object} errInfo "reason of fail"
+// @Failure 401 {object} errInfo "invalid jwt"
+// @Failure 503 {object} errInfo "under maintenance"
+// @Router /rail_lines [delete]
+func RemoveRailLine(c *gin.Context) {
+	o := c.MustGet(keyOwner).(*entities.Player)
+	params := removeRequest{}
+	if err := c.ShouldBind(&params); err != nil {
+		c.Set(keyErr, err)
+	} else if err := services.RemoveRailLine(o, params.ID); err != nil {
+		c.Set(keyErr, err)
+	} else {
+		c.Set(keyOk, &removeResponse{params.ID})
+	}
+}
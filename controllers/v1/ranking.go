@@ -0,0 +1,70 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yasshi2525/RushHour/services"
+)
+
+// defaultRankingLimit is page size when limit is omitted
+const defaultRankingLimit = 20
+
+// rankingRequest represents requirement to view ranking
+type rankingRequest struct {
+	// Window is aggregation period
+	Window string `form:"window" json:"window" validate:"omitempty,oneof=hour day all"`
+	// Sort is statistics which ranking is sorted by
+	Sort string `form:"sort" json:"sort" validate:"omitempty,oneof=delivered commute revenue network"`
+	// Offset is the number of skipped ranks
+	Offset int `form:"offset" json:"offset" validate:"gte=0"`
+	// Limit is the number of ranks in one page
+	Limit int `form:"limit" json:"limit" validate:"omitempty,gte=1,lte=100"`
+}
+
+// export converts request to ranking parameters
+func (v *rankingRequest) export() (string, time.Duration, int, int) {
+	var window time.Duration
+	switch v.Window {
+	case "hour":
+		window = time.Hour
+	case "day":
+		window = 24 * time.Hour
+	}
+	limit := v.Limit
+	if limit == 0 {
+		limit = defaultRankingLimit
+	}
+	return v.Sort, window, v.Offset, limit
+}
+
+type rankingResponse struct {
+	Total    int              `json:"total"`
+	Contents []*services.Rank `json:"ranking"`
+}
+
+// Ranking returns leaderboard of players
+// It reads published view, so it must be called after ViewHandler instead of ModelHandler.
+// @Description leaderboard of players
+// @Tags rankingResponse
+// @Summary leaderboard of players
+// @Accept json
+// @Produce json
+// @Param window query string false "aggregation period (hour, day, all)"
+// @Param sort query string false "sort key (delivered, commute, revenue, network)"
+// @Param offset query integer false "the number of skipped ranks"
+// @Param limit query integer false "page size (1-100)"
+// @Success 200 {object} rankingResponse "ranking"
+// @Failure 400 {object} errInfo "reason of fail"
+// @Failure 503 {object} errInfo "under maintenance"
+// @Router /ranking [get]
+func Ranking(c *gin.Context) {
+	params := rankingRequest{}
+	if err := c.ShouldBind(&params); err != nil {
+		c.Set(keyErr, err)
+	} else {
+		list, total := services.Ranking(params.export())
+		c.Set(keyOk, &rankingResponse{Total: total, Contents: list})
+	}
+}
@@ -0,0 +1,62 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/yasshi2525/RushHour/services"
+)
+
+func TestValidRankingRequest(t *testing.T) {
+	v := initValidate()
+	cases := []struct {
+		in   rankingRequest
+		want []string
+	}{
+		{
+			in:   rankingRequest{},
+			want: nil,
+		}, {
+			in:   rankingRequest{Window: "day", Sort: "revenue", Offset: 10, Limit: 100},
+			want: nil,
+		}, {
+			// unknown window
+			in:   rankingRequest{Window: "week"},
+			want: []string{"Key: 'rankingRequest.window' Error:Field validation for 'window' failed on the 'oneof' tag"},
+		}, {
+			// unknown sort key
+			in:   rankingRequest{Sort: "money"},
+			want: []string{"Key: 'rankingRequest.sort' Error:Field validation for 'sort' failed on the 'oneof' tag"},
+		}, {
+			// negative offset
+			in:   rankingRequest{Offset: -1},
+			want: []string{"Key: 'rankingRequest.offset' Error:Field validation for 'offset' failed on the 'gte' tag"},
+		}, {
+			// too large limit
+			in:   rankingRequest{Limit: 101},
+			want: []string{"Key: 'rankingRequest.limit' Error:Field validation for 'limit' failed on the 'lte' tag"},
+		},
+	}
+
+	for _, c := range cases {
+		assertValidation("validRankingRequest", t, v, c.in, c.want)
+	}
+}
+
+func TestRanking(t *testing.T) {
+	w, _, r := prepare(ViewHandler())
+	r.GET("/ranking", Ranking)
+	assertOkResponse(t, paramAssertOk{
+		Method: "GET",
+		Path:   "/ranking?window=hour&limit=1",
+		R:      r,
+		W:      w,
+		Assert: func(got map[string]interface{}) {
+			if total := int(got["total"].(float64)); total != len(services.Model.Players) {
+				t.Errorf("/ranking.total got %d, want %d", total, len(services.Model.Players))
+			}
+			if list := got["ranking"].([]interface{}); len(list) > 1 {
+				t.Errorf("/ranking.ranking got %d, want <= 1", len(list))
+			}
+		},
+	})
+}
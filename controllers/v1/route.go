@@ -0,0 +1,38 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/yasshi2525/RushHour/entities"
+	"github.com/yasshi2525/RushHour/services"
+)
+
+// routeRequest represents requirement to view route towards Company
+type routeRequest struct {
+	// Company is id of goal
+	Company uint `form:"cid" json:"cid" validate:"required,numeric"`
+}
+
+// DumpRoute returns shortest path tree towards Company
+// @Description shortest path tree towards company as JSON and Graphviz DOT, and residences which can't reach it
+// @Tags services.RouteDump
+// @Summary dump route
+// @Accept json
+// @Produce json
+// @Param cid query integer true "company id"
+// @Success 200 {object} services.RouteDump "route"
+// @Failure 400 {object} errInfo "reason of fail"
+// @Failure 401 {object} errInfo "invalid jwt"
+// @Router /route [get]
+func DumpRoute(c *gin.Context) {
+	params := routeRequest{}
+	if err := c.ShouldBind(&params); err != nil {
+		c.Set(keyErr, err)
+	} else if cp, err := validateEntity(entities.COMPANY, params.Company); err != nil {
+		c.Set(keyErr, err)
+	} else if res, err := services.DumpRoute(cp.(*entities.Company)); err != nil {
+		c.Set(keyErr, err)
+	} else {
+		c.Set(keyOk, res)
+	}
+}
@@ -0,0 +1,58 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yasshi2525/RushHour/services"
+)
+
+// DownloadSnapshot returns whole world as gzipped JSON
+// It takes lock of model by itself, so it must not be called after ModelHandler.
+// @Description whole world as versioned snapshot file
+// @Tags services.Snapshot
+// @Summary download snapshot
+// @Produce application/gzip
+// @Success 200 {object} services.Snapshot "gzipped snapshot"
+// @Failure 500 {object} errInfo "reason of fail"
+// @Failure 401 {object} errInfo "invalid jwt"
+// @Router /snapshot [get]
+func DownloadSnapshot(c *gin.Context) {
+	name := fmt.Sprintf("rushhour-%s.json.gz", time.Now().Format("20060102-150405"))
+	c.Header("Content-Type", "application/gzip")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", name))
+	c.Status(http.StatusOK)
+	if err := services.SaveSnapshot(c.Writer, true); err != nil {
+		// header may be already sent
+		c.AbortWithStatusJSON(http.StatusInternalServerError, &errInfo{Err: []string{err.Error()}})
+	}
+}
+
+// UploadSnapshot returns result of replacing whole world with snapshot
+// @Description result of loading snapshot. game must be stopped.
+// @Tags gameStatus
+// @Summary upload snapshot
+// @Accept multipart/form-data
+// @Produce json
+// @Param snapshot formData file true "gzipped snapshot"
+// @Success 200 {object} gameStatus "game status"
+// @Failure 400 {object} errInfo "reason of fail"
+// @Failure 401 {object} errInfo "invalid jwt"
+// @Router /snapshot [post]
+func UploadSnapshot(c *gin.Context) {
+	if fh, err := c.FormFile("snapshot"); err != nil {
+		c.Set(keyErr, err)
+	} else if f, err := fh.Open(); err != nil {
+		c.Set(keyErr, err)
+	} else {
+		defer f.Close()
+		if err := services.LoadSnapshot(f, false); err != nil {
+			c.Set(keyErr, err)
+		} else {
+			c.Set(keyOk, newGameStatus())
+		}
+	}
+}
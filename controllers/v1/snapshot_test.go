@@ -0,0 +1,39 @@
+package v1
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/yasshi2525/RushHour/services"
+)
+
+func TestUploadSnapshot(t *testing.T) {
+	w, _, r := prepare(ModelHandler())
+	r.POST("/snapshot", UploadSnapshot)
+	req, _ := http.NewRequest("POST", "/snapshot", nil)
+	r.ServeHTTP(w, req)
+	assertErrorResponse("/snapshot", t, w, []string{"request Content-Type isn't multipart/form-data"})
+}
+
+func TestDownloadSnapshot(t *testing.T) {
+	w, _, r := prepare()
+	r.GET("/snapshot", DownloadSnapshot)
+	req, _ := http.NewRequest("GET", "/snapshot", nil)
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("/snapshot.code got %d, want %d (details = %s)", w.Code, http.StatusOK, w.Body.String())
+	}
+	gr, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("/snapshot.body got %v, want gzip", err)
+	}
+	var snap services.Snapshot
+	if err := json.NewDecoder(gr).Decode(&snap); err != nil {
+		t.Fatalf("/snapshot.body got %v, want snapshot", err)
+	}
+	if snap.Version != services.SnapshotVersion {
+		t.Errorf("/snapshot.version got %d, want %d", snap.Version, services.SnapshotVersion)
+	}
+}
@@ -0,0 +1,86 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+
+	"github.com/yasshi2525/RushHour/entities"
+	"github.com/yasshi2525/RushHour/services"
+)
+
+type stationRequest struct {
+	RailNode uint   `form:"rnid" json:"rnid" validate:"required,numeric"`
+	Name     string `form:"name" json:"name" validate:"omitempty,max=64"`
+}
+
+type stationResponse struct {
+	Station  *entities.Station          `json:"st"`
+	Platform *entities.DelegatePlatform `json:"p"`
+}
+
+// CreateStation returns result of station creation
+// @Description result of station creation over rail node
+// @Tags stationResponse
+// @Summary create station
+// @Accept json
+// @Produce json
+// @Param scale body number true "width,height(100%)=2^scale"
+// @Param rnid body integer true "rail node id"
+// @Param name body string false "station name"
+// @Success 200 {object} stationResponse "created station"
+// @Failure 400 {object} errInfo "reason of fail"
+// @Failure 401 {object} errInfo "invalid jwt"
+// @Failure 503 {object} errInfo "under maintenance"
+// @Router /stations [post]
+func CreateStation(c *gin.Context) {
+	o := c.MustGet(keyOwner).(*entities.Player)
+	sc := scaleRequest{}
+	if err := c.ShouldBindBodyWith(&sc, binding.JSON); err != nil {
+		c.Set(keyErr, err)
+	} else {
+		params := stationRequest{}
+		if err := c.ShouldBindBodyWith(&params, binding.JSON); err != nil {
+			c.Set(keyErr, err)
+		} else if rn, err := validateEntity(entities.RAILNODE, params.RailNode); err != nil {
+			c.Set(keyErr, err)
+		} else if st, err := services.CreateStation(o, rn.(*entities.RailNode), params.Name); err != nil {
+			c.Set(keyErr, err)
+		} else if p, err := services.FindDelegatePlatform(st.Platform, sc.Scale); err != nil {
+			c.Set(keyErr, err)
+		} else {
+			c.Set(keyOk, &stationResponse{st, p})
+		}
+	}
+}
+
+type removeRequest struct {
+	ID uint `form:"id" json:"id" validate:"required,numeric"`
+}
+
+type removeResponse struct {
+	ID uint `json:"id"`
+}
+
+// RemoveStation returns result of station deletion
+// @Description result of station deletion
+// @Tags removeResponse
+// @Summary remove station
+// @Accept json
+// @Produce json
+// @Param id body integer true "station id"
+// @Success 200 {object} removeResponse "removed station"
+// @Failure 400 {object} errInfo "reason of fail"
+// @Failure 401 {object} errInfo "invalid jwt"
+// @Failure 503 {object} errInfo "under maintenance"
+// @Router /stations [delete]
+func RemoveStation(c *gin.Context) {
+	o := c.MustGet(keyOwner).(*entities.Player)
+	params := removeRequest{}
+	if err := c.ShouldBind(&params); err != nil {
+		c.Set(keyErr, err)
+	} else if err := services.RemoveStation(o, params.ID); err != nil {
+		c.Set(keyErr, err)
+	} else {
+		c.Set(keyOk, &removeResponse{params.ID})
+	}
+}
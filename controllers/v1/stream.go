@@ -0,0 +1,56 @@
+package v1
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+	"gopkg.in/go-playground/validator.v9"
+
+	"github.com/yasshi2525/RushHour/services"
+)
+
+// Stream pushes changes of gamemap through WebSocket
+// @Description first message contains all entities in specified area as "create" events,
+// @Description following messages contain created, updated and deleted entities after each game procedure
+// @Tags services.MapEvents
+// @Summary subscribe changes of entities in specified area
+// @Produce json
+// @Param x query number true "x coordinate"
+// @Param y query number true "y coordinate"
+// @Param scale query number true "width,height(100%)=2^scale"
+// @Param delegate query number true "width,height(grid)=2^delegate"
+// @Success 101 {object} services.MapEvents "events of entities in specified area"
+// @Failure 400 {object} errInfo "reasons of error when x, y and scale are out of area"
+// @Failure 503 {object} errInfo "under maintenance"
+// @Router /gamemap/stream [get]
+func Stream(c *gin.Context) {
+	params := gameMapRequest{}
+	if err := c.ShouldBindQuery(&params); err != nil {
+		if verr, ok := err.(validator.ValidationErrors); ok {
+			c.JSON(http.StatusBadRequest, buildErrorMessages(verr))
+		} else {
+			c.JSON(http.StatusBadRequest, &errInfo{Err: []string{err.Error()}})
+		}
+		return
+	}
+	srv := websocket.Server{Handler: func(ws *websocket.Conn) {
+		sub := services.Subscribe(params.export())
+		defer services.Unsubscribe(sub)
+
+		// client sends nothing, so reading returns only when connection is closed
+		go func() {
+			io.Copy(ioutil.Discard, ws)
+			services.Unsubscribe(sub)
+		}()
+
+		for msg := range sub.C {
+			if err := websocket.JSON.Send(ws, msg); err != nil {
+				return
+			}
+		}
+	}}
+	srv.ServeHTTP(c.Writer, c.Request)
+}
@@ -0,0 +1,52 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/websocket"
+
+	"github.com/yasshi2525/RushHour/services"
+)
+
+func TestStream(t *testing.T) {
+	_, _, r := prepare()
+	r.GET("/gamemap/stream", Stream)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	t.Run("ok", func(t *testing.T) {
+		url := fmt.Sprintf("ws%s/gamemap/stream?x=0&y=0&scale=%d&delegate=0",
+			strings.TrimPrefix(srv.URL, "http"), conf.Game.Entity.MaxScale)
+		ws, err := websocket.Dial(url, "", srv.URL)
+		if err != nil {
+			t.Fatalf("%s got %v, want nil", url, err)
+		}
+		defer ws.Close()
+
+		var msg services.MapEvents
+		if err := websocket.JSON.Receive(ws, &msg); err != nil {
+			t.Errorf("%s.receive got %v, want nil", url, err)
+		}
+		for _, e := range msg.Events {
+			if e.Op != "create" {
+				t.Errorf("%s.op got %s, want create", url, e.Op)
+			}
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		res, err := http.Get(fmt.Sprintf("%s/gamemap/stream?x=0&y=0&scale=%d&delegate=0",
+			srv.URL, conf.Game.Entity.MaxScale+1))
+		if err != nil {
+			t.Fatalf("get got %v, want nil", err)
+		}
+		defer res.Body.Close()
+		if res.StatusCode != http.StatusBadRequest {
+			t.Errorf("code got %d, want %d", res.StatusCode, http.StatusBadRequest)
+		}
+	})
+}
@@ -0,0 +1,141 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+
+	"github.com/yasshi2525/RushHour/entities"
+	"github.com/yasshi2525/RushHour/services"
+)
+
+type trainRequest struct {
+	Name string `form:"name" json:"name" validate:"omitempty,max=64"`
+}
+
+type trainResponse struct {
+	Train *entities.Train `json:"t"`
+}
+
+// CreateTrain returns result of train purchase
+// @Description result of train purchase. Train runs after it is deployed on rail line.
+// @Tags trainResponse
+// @Summary create train
+// @Accept json
+// @Produce json
+// @Param name body string false "train name"
+// @Success 200 {object} trainResponse "created train"
+// @Failure 400 {object} errInfo "reason of fail"
+// @Failure 401 {object} errInfo "invalid jwt"
+// @Failure 503 {object} errInfo "under maintenance"
+// @Router /trains [post]
+func CreateTrain(c *gin.Context) {
+	o := c.MustGet(keyOwner).(*entities.Player)
+	params := trainRequest{}
+	if err := c.ShouldBind(&params); err != nil {
+		c.Set(keyErr, err)
+	} else if t, err := services.CreateTrain(o, params.Name); err != nil {
+		c.Set(keyErr, err)
+	} else {
+		c.Set(keyOk, &trainResponse{t})
+	}
+}
+
+type deployTrainRequest struct {
+	Train    uint `form:"tid" json:"tid" validate:"required,numeric"`
+	RailLine uint `form:"lid" json:"lid" validate:"required,numeric"`
+}
+
+type deployTrainResponse struct {
+	Train *entities.DelegateTrain `json:"t"`
+}
+
+// DeployTrain returns result of train deployment
+// @Description result of train deployment on ringed rail line
+// @Tags deployTrainResponse
+// @Summary deploy train
+// @Accept json
+// @Produce json
+// @Param scale body number true "width,height(100%)=2^scale"
+// @Param tid body integer true "train id"
+// @Param lid body integer true "rail line id"
+// @Success 200 {object} deployTrainResponse "deployed train"
+// @Failure 400 {object} errInfo "reason of fail"
+// @Failure 401 {object} errInfo "invalid jwt"
+// @Failure 503 {object} errInfo "under maintenance"
+// @Router /trains/deploy [post]
+func DeployTrain(c *gin.Context) {
+	o := c.MustGet(keyOwner).(*entities.Player)
+	sc := scaleRequest{}
+	if err := c.ShouldBindBodyWith(&sc, binding.JSON); err != nil {
+		c.Set(keyErr, err)
+	} else {
+		params := deployTrainRequest{}
+		if err := c.ShouldBindBodyWith(&params, binding.JSON); err != nil {
+			c.Set(keyErr, err)
+		} else if t, err := validateEntity(entities.TRAIN, params.Train); err != nil {
+			c.Set(keyErr, err)
+		} else if l, err := validateEntity(entities.RAILLINE, params.RailLine); err != nil {
+			c.Set(keyErr, err)
+		} else if err := services.DeployTrain(o, t.(*entities.Train), l.(*entities.RailLine)); err != nil {
+			c.Set(keyErr, err)
+		} else if dt, err := services.FindDelegateTrain(t.(*entities.Train), sc.Scale); err != nil {
+			c.Set(keyErr, err)
+		} else {
+			c.Set(keyOk, &deployTrainResponse{dt})
+		}
+	}
+}
+
+type unDeployTrainRequest struct {
+	Train uint `form:"tid" json:"tid" validate:"required,numeric"`
+}
+
+// UnDeployTrain returns result of train withdrawal
+// @Description result of train withdrawal from rail line. Passengers get off forcefully.
+// @Tags trainResponse
+// @Summary undeploy train
+// @Accept json
+// @Produce json
+// @Param tid body integer true "train id"
+// @Success 200 {object} trainResponse "undeployed train"
+// @Failure 400 {object} errInfo "reason of fail"
+// @Failure 401 {object} errInfo "invalid jwt"
+// @Failure 503 {object} errInfo "under maintenance"
+// @Router /trains/undeploy [post]
+func UnDeployTrain(c *gin.Context) {
+	o := c.MustGet(keyOwner).(*entities.Player)
+	params := unDeployTrainRequest{}
+	if err := c.ShouldBind(&params); err != nil {
+		c.Set(keyErr, err)
+	} else if t, err := validateEntity(entities.TRAIN, params.Train); err != nil {
+		c.Set(keyErr, err)
+	} else if err := services.UnDeployTrain(o, t.(*entities.Train)); err != nil {
+		c.Set(keyErr, err)
+	} else {
+		c.Set(keyOk, &trainResponse{t.(*entities.Train)})
+	}
+}
+
+// RemoveTrain returns result of train deletion
+// @Description result of train deletion
+// @Tags removeResponse
+// @Summary remove train
+// @Accept json
+// @Produce json
+// @Param id body integer true "train id"
+// @Success 200 {object} removeResponse "removed train"
+// @Failure 400 {object} errInfo "reason of fail"
+// @Failure 401 {object} errInfo "invalid jwt"
+// @Failure 503 {object} errInfo "under maintenance"
+// @Router /trains [delete]
+func RemoveTrain(c *gin.Context) {
+	o := c.MustGet(keyOwner).(*entities.Player)
+	params := removeRequest{}
+	if err := c.ShouldBind(&params); err != nil {
+		c.Set(keyErr, err)
+	} else if err := services.RemoveTrain(o, params.ID); err != nil {
+		c.Set(keyErr, err)
+	} else {
+		c.Set(keyOk, &removeResponse{params.ID})
+	}
+}
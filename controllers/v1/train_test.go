@@ -0,0 +1,112 @@
+package v1
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yasshi2525/RushHour/entities"
+	"github.com/yasshi2525/RushHour/services"
+)
+
+func TestRailway(t *testing.T) {
+	token := registerTestUser(t, "railway@example.com", "password")
+	bkWorker := conf.Game.Service.Routing.Worker
+	conf.Game.Service.Routing.Worker = 1
+	defer func() { conf.Game.Service.Routing.Worker = bkWorker }()
+	scale := conf.Game.Entity.MaxScale
+
+	call := func(method string, path string, handler gin.HandlerFunc, in interface{}) map[string]interface{} {
+		t.Helper()
+		var res map[string]interface{}
+		w, _, r := prepare(JWTHandler(), ModelHandler())
+		r.Handle(method, path, handler)
+		assertOkResponse(t, paramAssertOk{
+			Method: method,
+			Path:   path,
+			Jwt:    token,
+			R:      r,
+			W:      w,
+			In:     in,
+			Assert: func(got map[string]interface{}) {
+				res = got
+			},
+		})
+		if res == nil {
+			t.FailNow()
+		}
+		return res
+	}
+	// findRailNode returns RailNode created by API
+	findRailNode := func(x float64, y float64) *entities.RailNode {
+		t.Helper()
+		for _, rn := range services.Model.RailNodes {
+			if rn.X == x && rn.Y == y {
+				return rn
+			}
+		}
+		t.Fatalf("no rail node at (%f, %f)", x, y)
+		return nil
+	}
+
+	// rn1 <=> rn2
+	call("POST", "/rail_nodes", Depart, gin.H{"x": 0.5, "y": 0.5, "scale": scale})
+	rn1 := findRailNode(0.5, 0.5)
+	call("POST", "/rail_nodes/extend", Extend, gin.H{"x": 1, "y": 1, "scale": scale, "rnid": rn1.ID})
+	rn2 := findRailNode(1, 1)
+	var re uint
+	for id := range rn1.OutEdges {
+		re = id
+	}
+
+	res := call("POST", "/stations", CreateStation, gin.H{"rnid": rn1.ID, "name": "first", "scale": scale})
+	if st, ok := res["st"].(map[string]interface{}); !ok || st["name"] != "first" {
+		t.Errorf("/stations.st got %v, want name = first", res["st"])
+	}
+	if _, ok := res["p"].(map[string]interface{}); !ok {
+		t.Errorf("/stations.p got %v, want delegate platform", res["p"])
+	}
+	call("POST", "/stations", CreateStation, gin.H{"rnid": rn2.ID, "name": "second", "scale": scale})
+	p1 := rn1.OverPlatform.ID
+
+	res = call("POST", "/rail_lines", CreateRailLine, gin.H{"name": "line"})
+	lid := uint(res["l"].(map[string]interface{})["id"].(float64))
+
+	call("POST", "/rail_lines/start", StartRailLine, gin.H{"lid": lid, "pid": p1, "scale": scale})
+	call("POST", "/rail_lines/insert", InsertRailLine, gin.H{"lid": lid, "reid": re, "scale": scale})
+	res = call("POST", "/rail_lines/ring", RingRailLine, gin.H{"lid": lid, "scale": scale})
+	if res["ringed"] != true {
+		t.Errorf("/rail_lines/ring.ringed got %v, want true", res["ringed"])
+	}
+	if lts, ok := res["lts"].([]interface{}); !ok || len(lts) == 0 {
+		t.Errorf("/rail_lines/ring.lts got %v, want not empty", res["lts"])
+	}
+
+	res = call("POST", "/trains", CreateTrain, gin.H{"name": "train"})
+	tid := uint(res["t"].(map[string]interface{})["id"].(float64))
+
+	res = call("POST", "/trains/deploy", DeployTrain, gin.H{"tid": tid, "lid": lid, "scale": scale})
+	if got := res["t"].(map[string]interface{})["cid"]; got != float64(tid) {
+		t.Errorf("/trains/deploy.t.cid got %v, want %d", got, tid)
+	}
+
+	res = call("POST", "/trains/undeploy", UnDeployTrain, gin.H{"tid": tid})
+	if got := res["t"].(map[string]interface{})["ltid"]; got != nil {
+		t.Errorf("/trains/undeploy.t.ltid got %v, want nil", got)
+	}
+
+	for _, c := range []struct {
+		path    string
+		handler gin.HandlerFunc
+		id      uint
+	}{
+		{"/trains", RemoveTrain, tid},
+		{"/rail_lines", RemoveRailLine, lid},
+	} {
+		res = call("DELETE", c.path, c.handler, gin.H{"id": c.id})
+		if got := fmt.Sprintf("%v", res["id"]); got != fmt.Sprintf("%d", c.id) {
+			t.Errorf("%s.id got %s, want %d", c.path, got, c.id)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package entities
 import (
 	"fmt"
 	"reflect"
+	"time"
 )
 
 // Chunk represents square area. Many Entities are deployed over Chunk.
@@ -13,11 +14,15 @@ type Chunk struct {
 	Residence *DelegateResidence
 	Company   *DelegateCompany
 	RailNode  *DelegateRailNode
+	Platform  *DelegatePlatform
+	Train     *DelegateTrain
 
 	Parent *Cluster
 
 	InRailEdges  map[uint]*DelegateRailEdge
 	OutRailEdges map[uint]*DelegateRailEdge
+	InLineTasks  map[uint]*DelegateLineTask
+	OutLineTasks map[uint]*DelegateLineTask
 }
 
 // NewChunk create Chunk on specified Cluster
@@ -42,6 +47,8 @@ func (ch *Chunk) Init(m *Model) {
 	ch.Base.Init(CHUNK, m)
 	ch.InRailEdges = make(map[uint]*DelegateRailEdge)
 	ch.OutRailEdges = make(map[uint]*DelegateRailEdge)
+	ch.InLineTasks = make(map[uint]*DelegateLineTask)
+	ch.OutLineTasks = make(map[uint]*DelegateLineTask)
 }
 
 // Add deploy Entity over Chunk
@@ -62,6 +69,7 @@ func (ch *Chunk) addLocalable(obj Localable) {
 	if !nodeField.IsValid() {
 		return
 	}
+	ch.touch()
 
 	if nodeField.IsNil() {
 		var pids []uint
@@ -93,6 +101,7 @@ func (ch *Chunk) addConnectable(obj Connectable) {
 		// ex. no OutSteps in DelegateNode
 		return
 	}
+	ch.touch()
 
 	if !outMap.MapIndex(toID).IsValid() {
 		nodeFieldName := connectTypes[obj.B().T].String()
@@ -124,6 +133,8 @@ func (ch *Chunk) setReverse(dre *DelegateRailEdge, toCh *Chunk) {
 		dre.ReverseID = reverse.ID
 		reverse.Reverse = dre
 		reverse.ReverseID = dre.ID
+		reverse.ChangedAt = time.Now()
+		toCh.touch()
 	}
 }
 
@@ -141,13 +152,17 @@ func (ch *Chunk) removeLocalable(obj Localable) {
 	fieldName := obj.B().T.String()
 	nodeField := reflect.ValueOf(ch).Elem().FieldByName(fieldName)
 
-	if !nodeField.IsValid() {
+	if !nodeField.IsValid() || nodeField.IsNil() ||
+		!nodeField.Elem().FieldByName("List").MapIndex(reflect.ValueOf(obj.B().ID)).IsValid() {
+		// ex. Train not deployed because it has no LineTask
 		return
 	}
+	ch.touch()
 
 	nodeField.MethodByName("Remove").Call([]reflect.Value{reflect.ValueOf(obj)})
 
 	if nodeField.Elem().FieldByName("List").Len() == 0 {
+		ch.M.bury(ch, obj.B().T, uint(nodeField.Elem().FieldByName("ID").Uint()))
 		nodeField.Set(reflect.Zero(nodeField.Type()))
 	}
 }
@@ -167,11 +182,13 @@ func (ch *Chunk) removeConnectable(obj Connectable) {
 		// ex. no OutSteps in DelegateNode
 		return
 	}
+	ch.touch()
 
 	delegate := outMap.MapIndex(toID)
 	delegate.MethodByName("Remove").Call([]reflect.Value{reflect.ValueOf(obj)})
 
 	if delegate.Elem().FieldByName("List").Len() == 0 {
+		ch.M.bury(ch, obj.B().T, uint(delegate.Elem().FieldByName("ID").Uint()))
 		outMap.SetMapIndex(toID, reflect.ValueOf(nil))
 		inMapName := fmt.Sprintf("In%ss", obj.B().T.String())
 		inMap := reflect.ValueOf(toCh).Elem().FieldByName(inMapName)
@@ -211,9 +228,28 @@ func (ch *Chunk) Has(raw Entity) bool {
 	return false
 }
 
+// touchLocalable records that delegate of specified Entity is changed without moving Chunk.
+func (ch *Chunk) touchLocalable(obj Entity) {
+	nodeField := reflect.ValueOf(ch).Elem().FieldByName(obj.B().T.String())
+	nodeField.Elem().FieldByName("ChangedAt").Set(reflect.ValueOf(time.Now()))
+	ch.touch()
+}
+
+// touch records that delegates over Chunk and its ancestor Clusters are changed.
+func (ch *Chunk) touch() {
+	now := time.Now()
+	ch.ChangedAt = now
+	for cl := ch.Parent; cl != nil; cl = cl.Parent {
+		cl.ChangedAt = now
+	}
+}
+
 // IsEmpty returns whether any Entity is deployed over Chunk or not.
 func (ch *Chunk) IsEmpty() bool {
-	return ch.RailNode == nil
+	return ch.Residence == nil && ch.Company == nil && ch.RailNode == nil &&
+		ch.Platform == nil && ch.Train == nil &&
+		len(ch.InRailEdges) == 0 && len(ch.OutRailEdges) == 0 &&
+		len(ch.InLineTasks) == 0 && len(ch.OutLineTasks) == 0
 }
 
 // CheckDelete check remaining reference.
@@ -256,12 +292,22 @@ func (ch *Chunk) Export(dm *DelegateMap) {
 		dm.Add(re)
 		dm.Add(re.To)
 	}
+	dm.Add(ch.Platform)
+	dm.Add(ch.Train)
+	for _, lt := range ch.InLineTasks {
+		dm.Add(lt)
+		dm.Add(lt.From)
+	}
+	for _, lt := range ch.OutLineTasks {
+		dm.Add(lt)
+		dm.Add(lt.To)
+	}
 }
 
 // String represents status
 func (ch *Chunk) String() string {
-	return fmt.Sprintf("%s(%d:%d):u=%d,r=%v,c=%v,rn=%v,i=%d,o=%d:%v", ch.T.Short(),
+	return fmt.Sprintf("%s(%d:%d):u=%d,r=%v,c=%v,rn=%v,p=%v,t=%v,i=%d,o=%d:%v", ch.T.Short(),
 		ch.Parent.Scale, ch.ID, ch.OwnerID,
-		ch.Residence, ch.Company, ch.RailNode,
+		ch.Residence, ch.Company, ch.RailNode, ch.Platform, ch.Train,
 		len(ch.InRailEdges), len(ch.OutRailEdges), ch.ChunkPoint)
 }
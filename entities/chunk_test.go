@@ -44,5 +44,43 @@ func TestChunk(t *testing.T) {
 				{"re", dreFrom == dreTo, true},
 			}.Assert(t)
 		})
+
+		t.Run("reverse", func(t *testing.T) {
+			m := NewModel(config.CnfEntity{
+				MaxScale: 2,
+				MinScale: 1,
+			}, a)
+			o := m.NewPlayer()
+			n1 := m.NewRailNode(o, 0.5, 0.5)
+			n2, _ := n1.Extend(2.5, 0.5)
+
+			from := m.RootCluster.FindChunk(n1, m.conf.MinScale)
+			to := m.RootCluster.FindChunk(n2, m.conf.MinScale)
+			dre := from.OutRailEdges[to.ID]
+
+			TestCases{
+				{"chunk", from != to, true},
+				{"reverse", dre.ReverseID, to.OutRailEdges[from.ID].ID},
+				// delta since reverse was set must include it
+				{"from.touch", from.ChangedAt.Before(dre.ChangedAt), false},
+				{"root.touch", m.RootCluster.ChangedAt.Before(dre.ChangedAt), false},
+			}.Assert(t)
+		})
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		m := NewModel(config.CnfEntity{
+			MaxScale: 1,
+		}, a)
+		r := m.NewResidence(0, 0)
+		c := m.NewCompany(0, 0)
+		ch := m.RootCluster.Data[ZERO]
+		c.Delete()
+
+		TestCases{
+			{"Company", ch.Company, (*DelegateCompany)(nil)},
+			{"Residence", ch.Residence.List[r.ID], r},
+			{"Chunk", m.RootCluster.Data[ZERO], ch},
+		}.Assert(t)
 	})
 }
@@ -39,6 +39,12 @@ func (p *ChunkPoint) contains(oth *ChunkPoint) bool {
 	return p.X == oth.X>>diff && p.Y == oth.Y>>diff
 }
 
+// exports returns whether ViewMap exports delegates of this point or not.
+func (p *ChunkPoint) exports(pos *ChunkPoint, span int, maxScale int) bool {
+	return p.contains(pos) && p.Scale <= pos.Scale-span &&
+		(p.Scale == maxScale || p.Scale+1 > pos.Scale-span)
+}
+
 func (p *ChunkPoint) String() string {
 	return fmt.Sprintf("(%d,%d,%d)", p.X, p.Y, p.Scale)
 }
@@ -146,6 +152,19 @@ func (cl *Cluster) Update(obj Entity) {
 	cl.Add(obj)
 }
 
+// Touch records that delegates of specified Localable are changed without moving Chunk.
+func (cl *Cluster) Touch(obj Entity) {
+	oid := obj.B().OwnerID
+	if chunk := cl.Data[oid]; chunk != nil && chunk.Has(obj) {
+		chunk.touchLocalable(obj)
+		cl.eachChildren(func(dx int, dy int, c *Cluster, p *ChunkPoint) {
+			if c != nil {
+				c.Touch(obj)
+			}
+		})
+	}
+}
+
 // Remove undeploy specified Entity over related Chunk.
 func (cl *Cluster) Remove(obj Entity) {
 	if _, ok := obj.(Localable); ok {
@@ -175,11 +194,17 @@ func (cl *Cluster) removeEntity(obj Entity) {
 }
 
 // ViewMap set delegate Entity to DelegateMap.
+// Cluster not changed after dm.Since is skipped without scanning its children.
 func (cl *Cluster) ViewMap(dm *DelegateMap, pos *ChunkPoint, span int) {
+	if cl.ChangedAt.Before(dm.Since) {
+		return
+	}
 	if cl.ChunkPoint.contains(pos) {
 		if cl.Scale <= pos.Scale-span {
 			for _, d := range cl.Data {
-				d.Export(dm)
+				if !d.ChangedAt.Before(dm.Since) {
+					d.Export(dm)
+				}
 			}
 		} else {
 			cl.eachChildren(func(dx int, dy int, c *Cluster, p *ChunkPoint) {
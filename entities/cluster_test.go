@@ -1,7 +1,9 @@
 package entities
 
 import (
+	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/yasshi2525/RushHour/auth"
 	"github.com/yasshi2525/RushHour/config"
@@ -57,4 +59,139 @@ func TestCluster(t *testing.T) {
 			}.Assert(t)
 		})
 	})
+	t.Run("ViewMap", func(t *testing.T) {
+		t.Run("since", func(t *testing.T) {
+			m := NewModel(config.CnfEntity{
+				MaxScale: 1,
+			}, a)
+			pos := &ChunkPoint{Scale: m.conf.MaxScale}
+			m.NewResidence(0, 0)
+			since := time.Now()
+			c := m.NewCompany(0, 0)
+			dc := m.RootCluster.Data[ZERO].Company
+
+			dm := &DelegateMap{}
+			dm.Init()
+			dm.Since = since
+			m.RootCluster.ViewMap(dm, pos, 0)
+
+			TestCases{
+				{"r", len(dm.Residences), 0},
+				{"c", dm.Companies[dc.ID], dc},
+			}.Assert(t)
+
+			since = time.Now()
+			c.Delete()
+
+			dm = &DelegateMap{}
+			dm.Init()
+			dm.Since = since
+			m.RootCluster.ViewMap(dm, pos, 0)
+			m.ViewTombstones(dm, pos, 0)
+
+			TestCases{
+				{"r", len(dm.Residences), 0},
+				{"c", len(dm.Companies), 0},
+				{"deletes", len(dm.Deletes[COMPANY.API()]), 1},
+				{"deletes.c", dm.Deletes[COMPANY.API()][0], dc.ID},
+			}.Assert(t)
+		})
+		t.Run("train", func(t *testing.T) {
+			m := NewModel(config.CnfEntity{
+				MaxScale: 2,
+				Platform: config.CnfPlatform{Capacity: 1},
+				Train:    config.CnfTrain{Speed: 1, Capacity: 3, Mobility: 1},
+			}, a)
+			pos := &ChunkPoint{Scale: m.conf.MaxScale}
+			o := m.NewPlayer()
+			n0 := m.NewRailNode(o, 0, 0)
+			_, e01 := n0.Extend(3, 0)
+			st := m.NewStation(o)
+			p0 := m.NewPlatform(n0, m.NewGate(st))
+
+			l := m.NewRailLine(o)
+			head := m.NewLineTaskDept(l, p0)
+			tail := m.NewLineTask(l, e01, head)
+			tail = m.NewLineTask(l, e01.Reverse, tail)
+			tail.SetNext(head)
+
+			tr := m.NewTrain(o, "test")
+			tr.SetTask(head)
+
+			dm := &DelegateMap{}
+			dm.Init()
+			m.RootCluster.ViewMap(dm, pos, 0)
+
+			var dp *DelegatePlatform
+			for _, v := range dm.Platforms {
+				dp = v
+			}
+			var dt *DelegateTrain
+			for _, v := range dm.Trains {
+				dt = v
+			}
+			var out struct {
+				Capacity int `json:"capacity"`
+				Mul      int `json:"mul"`
+			}
+			data, _ := json.Marshal(dt)
+			json.Unmarshal(data, &out)
+
+			var dlt *DelegateLineTask
+			for _, v := range dm.LineTasks {
+				dlt = v
+			}
+
+			TestCases{
+				{"p", len(dm.Platforms), 1},
+				{"p.st", dp.StationID, st.ID},
+				{"t", len(dm.Trains), 1},
+				{"t.cid", dt.ChildID, tr.ID},
+				{"t.capacity", out.Capacity, 3},
+				{"t.mul", out.Mul, 1},
+				// dept, n0 -> n1 and n1 -> n0 are over the same Chunk
+				{"lt", len(dm.LineTasks), 1},
+				{"lt.mul", dlt.Multi, 3},
+				{"lt.lids", len(dlt.RailLineIDs), 1},
+				{"lt.lid", dlt.RailLineIDs[0], l.ID},
+			}.Assert(t)
+
+			tr.Step(1)
+			tr.Step(2)
+			since := time.Now()
+			tr.Step(1)
+
+			dm = &DelegateMap{}
+			dm.Init()
+			dm.Since = since
+			m.RootCluster.ViewMap(dm, pos, 0)
+
+			TestCases{
+				{"moving", len(dm.Trains), 1},
+				{"rn", len(dm.RailNodes), 0},
+			}.Assert(t)
+
+			tr.SetTask(nil)
+			dm = &DelegateMap{}
+			dm.Init()
+			m.RootCluster.ViewMap(dm, pos, 0)
+
+			TestCases{
+				{"no task", len(dm.Trains), 0},
+			}.Assert(t)
+		})
+		t.Run("prune", func(t *testing.T) {
+			m := NewModel(config.CnfEntity{
+				MaxScale: 1,
+			}, a)
+			m.NewCompany(0, 0).Delete()
+			before := time.Now()
+			m.PruneTombstones(before)
+
+			TestCases{
+				{"tombstones", len(m.Tombstones), 0},
+				{"horizon", m.TombstoneHorizon, before},
+			}.Assert(t)
+		})
+	})
 }
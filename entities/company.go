@@ -13,8 +13,11 @@ type Company struct {
 	// Attract : if Attract is bigger, more Human destinate Company
 	Attract float64 `gorm:"not null" json:"attract"`
 	Name    string  `json:"name"`
+	// Demand is multiplier of commuters Company draws
+	Demand float64 `gorm:"not null;default:1" json:"demand"`
 
 	Targets map[uint]*Human `gorm:"-" json:"-"`
+	out     map[uint]*Step
 	in      map[uint]*Step
 }
 
@@ -25,12 +28,14 @@ func (m *Model) NewCompany(x float64, y float64) *Company {
 		Persistence: NewPersistence(),
 		Point:       NewPoint(x, y),
 		Attract:     m.conf.Company.Attract,
+		Demand:      initialDemand(m.conf.Company.Demand),
 	}
 	c.Init(m)
 	c.Resolve()
 	c.Marshal()
 	m.Add(c)
 
+	c.GenOutSteps()
 	c.GenInSteps()
 	return c
 }
@@ -50,6 +55,23 @@ func (c *Company) Pos() *Point {
 	return &c.Point
 }
 
+// Weight returns how likely Human destinates this Company.
+func (c *Company) Weight() float64 {
+	return c.Attract * c.Demand
+}
+
+// GenOutSteps generates Steps from this Company for going home.
+func (c *Company) GenOutSteps() {
+	// C -> R
+	for _, r := range c.M.Residences {
+		c.M.NewStep(c, r)
+	}
+	// C -> G
+	for _, g := range c.M.Gates {
+		c.M.NewStep(c, g)
+	}
+}
+
 // GenInSteps generates and registers Step for this Company.
 func (c *Company) GenInSteps() {
 	// R -> C
@@ -65,13 +87,14 @@ func (c *Company) GenInSteps() {
 // Init creates map.
 func (c *Company) Init(m *Model) {
 	c.Base.Init(COMPANY, m)
+	c.out = make(map[uint]*Step)
 	c.in = make(map[uint]*Step)
 	c.Targets = make(map[uint]*Human)
 }
 
 // OutSteps returns where it can go to.
 func (c *Company) OutSteps() map[uint]*Step {
-	return nil
+	return c.out
 }
 
 // InSteps returns where it comes from.
@@ -114,7 +137,10 @@ func (c *Company) CheckDelete() error {
 // Delete removes this entity with related ones.
 func (c *Company) Delete() {
 	for _, h := range c.Targets {
-		c.M.Delete(h)
+		h.Delete()
+	}
+	for _, s := range c.out {
+		c.M.Delete(s)
 	}
 	for _, s := range c.in {
 		c.M.Delete(s)
@@ -125,6 +151,6 @@ func (c *Company) Delete() {
 // String represents status
 func (c *Company) String() string {
 	c.Marshal()
-	return fmt.Sprintf("%s(%d):i=%d,o=0,h=%d:%v:%s", c.Type().Short(),
-		c.ID, len(c.in), len(c.Targets), c.Pos(), c.Name)
+	return fmt.Sprintf("%s(%d):i=%d,o=%d,h=%d:%v:%s", c.Type().Short(),
+		c.ID, len(c.in), len(c.out), len(c.Targets), c.Pos(), c.Name)
 }
@@ -1,8 +1,11 @@
 package entities
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
 	"reflect"
+	"sort"
 	"strings"
 	"time"
 )
@@ -17,8 +20,21 @@ type DelegateMap struct {
 	RailNodes map[uint]*DelegateRailNode `json:"rail_nodes"`
 	// RailEdges is the list of delegated RailEdge information
 	RailEdges map[uint]*DelegateRailEdge `json:"rail_edges"`
+	// Platforms is the list of delegated Platform information
+	Platforms map[uint]*DelegatePlatform `json:"platforms"`
+	// Trains is the list of delegated Train information
+	Trains map[uint]*DelegateTrain `json:"trains"`
+	// LineTasks is the list of delegated LineTask information
+	LineTasks map[uint]*DelegateLineTask `json:"line_tasks"`
+
+	// Deletes is the list of delegated id removed after Since
+	Deletes map[string][]uint `json:"deletes,omitempty"`
+	// Delta is true when map contains only delegates changed after Since
+	Delta bool `json:"delta"`
 
 	Values map[ModelType]reflect.Value `json:"-"`
+	// Since is the lower limit of ChangedAt. Zero value means whole map.
+	Since time.Time `json:"-"`
 
 	Timestamp int64 `json:"timestamp"`
 }
@@ -29,10 +45,14 @@ func (dm *DelegateMap) Init() {
 	dm.Companies = make(map[uint]*DelegateCompany)
 	dm.RailNodes = make(map[uint]*DelegateRailNode)
 	dm.RailEdges = make(map[uint]*DelegateRailEdge)
+	dm.Platforms = make(map[uint]*DelegatePlatform)
+	dm.Trains = make(map[uint]*DelegateTrain)
+	dm.LineTasks = make(map[uint]*DelegateLineTask)
+	dm.Deletes = make(map[string][]uint)
 
 	dm.Values = make(map[ModelType]reflect.Value)
 	v := reflect.ValueOf(dm).Elem()
-	for idx, ty := range []ModelType{RESIDENCE, COMPANY, RAILNODE, RAILEDGE} {
+	for idx, ty := range delegateTypeList {
 		dm.Values[ty] = v.Field(idx)
 	}
 
@@ -41,13 +61,29 @@ func (dm *DelegateMap) Init() {
 
 // Add add delegatable object to map
 func (dm *DelegateMap) Add(obj delegateLocalable) {
-	if reflect.ValueOf(obj).IsNil() {
+	if reflect.ValueOf(obj).IsNil() || obj.B().ChangedAt.Before(dm.Since) {
 		return
 	}
 	dm.Values[obj.B().Type()].SetMapIndex(
 		reflect.ValueOf(obj.B().Idx()), reflect.ValueOf(obj))
 }
 
+// Bury add removed delegate object to map
+func (dm *DelegateMap) Bury(tb *Tombstone) {
+	if tb.DeletedAt.Before(dm.Since) {
+		return
+	}
+	dm.Deletes[tb.Type.API()] = append(dm.Deletes[tb.Type.API()], tb.ID)
+}
+
+// Tombstone represents removal of delegate object over Chunk.
+type Tombstone struct {
+	ChunkPoint
+	Type      ModelType
+	ID        uint
+	DeletedAt time.Time
+}
+
 type delegateLocalable interface {
 	B() *Base
 }
@@ -86,6 +122,7 @@ func (ch *Chunk) NewDelegateNode(obj Localable, pids []uint) DelegateNode {
 
 // Add accepts new instance ant increment count variable
 func (dn *DelegateNode) Add(obj Localable) {
+	dn.ChangedAt = time.Now()
 	dn.List[obj.B().ID] = obj
 	dn.updateMulti()
 	scale := dn.Scale - dn.M.conf.MinScale
@@ -96,6 +133,7 @@ func (dn *DelegateNode) Add(obj Localable) {
 
 // Remove delete argument from list ant decrement count variable
 func (dn *DelegateNode) Remove(obj Localable) {
+	dn.ChangedAt = time.Now()
 	scale := dn.Scale - dn.M.conf.MinScale
 	dn.Pos.SumX -= Logarithm(obj.Pos().X, scale)
 	dn.Pos.SumY -= Logarithm(obj.Pos().Y, scale)
@@ -164,12 +202,14 @@ func (ch *Chunk) NewDelegateEdge(obj Connectable, from delegateLocalable, to del
 
 // Add accepts new instance ant increment count variable
 func (de *DelegateEdge) Add(obj Connectable) {
+	de.ChangedAt = time.Now()
 	de.List[obj.B().ID] = obj
 	de.updateMulti()
 }
 
 // Remove delete argument from list ant decrement count variable
 func (de *DelegateEdge) Remove(obj Connectable) {
+	de.ChangedAt = time.Now()
 	delete(de.List, obj.B().ID)
 	de.updateMulti()
 }
@@ -227,3 +267,112 @@ type DelegateRailEdge struct {
 func (dre DelegateRailEdge) B() *Base {
 	return &dre.Base
 }
+
+// DelegatePlatform is delegate of Platform
+type DelegatePlatform struct {
+	DelegateNode
+
+	// StationID is set when only one Platform is delegated
+	StationID uint `json:"stid,omitempty"`
+}
+
+// B returns reference of Base Object
+func (dp DelegatePlatform) B() *Base {
+	return &dp.Base
+}
+
+// Add accepts new instance and updates Station
+func (dp *DelegatePlatform) Add(obj Localable) {
+	dp.DelegateNode.Add(obj)
+	dp.updateStation()
+}
+
+// Remove delete argument from list and updates Station
+func (dp *DelegatePlatform) Remove(obj Localable) {
+	dp.DelegateNode.Remove(obj)
+	dp.updateStation()
+}
+
+func (dp *DelegatePlatform) updateStation() {
+	dp.StationID = ZERO
+	if child, ok := dp.List[dp.ChildID]; ok {
+		dp.StationID = child.(*Platform).InStation.ID
+	}
+}
+
+// DelegateTrain is delegate of Train.
+// Train is deployed over Chunk by the departure of current LineTask,
+// but its position and occupancy are calculated when it is marshaled
+// because Train moves every game procedure.
+type DelegateTrain struct {
+	DelegateNode
+}
+
+// B returns reference of Base Object
+func (dt DelegateTrain) B() *Base {
+	return &dt.Base
+}
+
+// MarshalJSON represents current position and occupancy of delegated Trains
+func (dt *DelegateTrain) MarshalJSON() ([]byte, error) {
+	type alias DelegateTrain
+	pos, occupied, capacity := &delegatePoint{}, 0, 0
+	scale := dt.Scale - dt.M.conf.MinScale
+	for _, obj := range dt.List {
+		t := obj.(*Train)
+		pos.AveX += math.Ldexp(t.X, -scale)
+		pos.AveY += math.Ldexp(t.Y, -scale)
+		occupied += t.Occupied
+		capacity += t.Capacity
+	}
+	if len(dt.List) > 0 {
+		pos.AveX /= float64(len(dt.List))
+		pos.AveY /= float64(len(dt.List))
+	}
+	return json.Marshal(&struct {
+		*alias
+		Pos      *delegatePoint `json:"pos"`
+		Occupied int            `json:"occupied"`
+		Capacity int            `json:"capacity"`
+	}{(*alias)(dt), pos, occupied, capacity})
+}
+
+// DelegateLineTask is delegate of LineTask.
+// It represents RailLines running between two Chunks.
+type DelegateLineTask struct {
+	DelegateEdge
+
+	// RailLineIDs is the sorted list of RailLine running over this edge
+	RailLineIDs []uint `json:"lids"`
+}
+
+// B returns reference of Base Object
+func (dlt DelegateLineTask) B() *Base {
+	return &dlt.Base
+}
+
+// Add accepts new instance and updates RailLines
+func (dlt *DelegateLineTask) Add(obj Connectable) {
+	dlt.DelegateEdge.Add(obj)
+	dlt.updateRailLines()
+}
+
+// Remove delete argument from list and updates RailLines
+func (dlt *DelegateLineTask) Remove(obj Connectable) {
+	dlt.DelegateEdge.Remove(obj)
+	dlt.updateRailLines()
+}
+
+func (dlt *DelegateLineTask) updateRailLines() {
+	found := make(map[uint]bool)
+	dlt.RailLineIDs = []uint{}
+	for _, obj := range dlt.List {
+		if lid := obj.(*LineTask).RailLine.ID; !found[lid] {
+			found[lid] = true
+			dlt.RailLineIDs = append(dlt.RailLineIDs, lid)
+		}
+	}
+	sort.Slice(dlt.RailLineIDs, func(i, j int) bool {
+		return dlt.RailLineIDs[i] < dlt.RailLineIDs[j]
+	})
+}
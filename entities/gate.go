@@ -69,6 +69,10 @@ func (g *Gate) GenOutSteps() {
 	for _, c := range g.M.Companies {
 		g.M.NewStep(g, c)
 	}
+	// G -> R
+	for _, r := range g.M.Residences {
+		g.M.NewStep(g, r)
+	}
 }
 
 // GenInSteps generates Steps to this Gate.
@@ -78,6 +82,16 @@ func (g *Gate) GenInSteps() {
 	for _, r := range g.M.Residences {
 		g.M.NewStep(r, g)
 	}
+	// C -> G
+	for _, c := range g.M.Companies {
+		g.M.NewStep(c, g)
+	}
+	// H -> G
+	for _, h := range g.M.Humans {
+		if h.On == OnGround {
+			g.M.NewStep(h, g)
+		}
+	}
 }
 
 // Init creates map.
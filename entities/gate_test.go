@@ -23,10 +23,10 @@ func TestGate(t *testing.T) {
 			{"stID", g.StationID, st.ID},
 			{"st.g", st.Gate, g},
 			{"st.gID", st.GateID, g.ID},
-			{"in", len(g.InSteps()), 1},
-			{"out", len(g.OutSteps()), 1},
+			{"in", len(g.InSteps()), 2},
+			{"out", len(g.OutSteps()), 2},
 			{"model", m.Gates[g.Idx()], g},
-			{"s", len(m.Steps), 3},
+			{"s", len(m.Steps), 6},
 		}.Assert(t)
 	})
 	t.Run("Delete", func(t *testing.T) {
@@ -42,7 +42,7 @@ func TestGate(t *testing.T) {
 
 		TestCases{
 			{"o", len(o.Gates), 0},
-			{"s", len(m.Steps), 1},
+			{"s", len(m.Steps), 2},
 			{"model", len(m.Gates), 0},
 		}.Assert(t)
 	})
@@ -15,9 +15,11 @@ const (
 	OnPlatform
 	// OnTrain represents Human ride on Train
 	OnTrain
+	// OnCompany represents Human works in Company and goes home later
+	OnCompany
 )
 
-// Human commute from Residence to Company by Train
+// Human commute from Residence to Company by Train and returns home after work
 type Human struct {
 	Base
 	Persistence
@@ -40,15 +42,27 @@ type Human struct {
 	// Progress is [0,1] value representing how much Human proceed current task.
 	Progress float64 `gorm:"not null" json:"progress"`
 
+	// Work represents how many seconds Human keeps working in Company.
+	Work float64 `gorm:"not null" json:"work"`
+
+	// Returning represents Human heads for Residence after working in Company.
+	Returning bool `gorm:"not null" json:"returning"`
+
 	On Standing `gorm:"-" json:"-"`
 
 	Current *Step `gorm:"-" json:"-"`
+	// Ride represents Transport Human will take from Platform.
+	Ride *Transport `gorm:"-" json:"-"`
 
 	From       *Residence `gorm:"-" json:"-"`
 	To         *Company   `gorm:"-" json:"-"`
 	onPlatform *Platform
 	onTrain    *Train
-	out        map[uint]*Step
+	// boarding represents Platform where Human got on Train.
+	boarding *Platform
+	// rode represents owners of Train Human has ever rode on.
+	rode map[uint]*Player
+	out  map[uint]*Step
 
 	FromID     uint `gorm:"not null" json:"rid"`
 	ToID       uint `gorm:"not null" json:"cid"`
@@ -56,15 +70,17 @@ type Human struct {
 	TrainID    uint `                json:"tid,omitempty"`
 }
 
-// NewHuman create instance
-func (m *Model) NewHuman(o *Player, x float64, y float64) *Human {
+// NewHuman create instance around Residence
+func (m *Model) NewHuman(r *Residence, c *Company) *Human {
 	h := &Human{
-		Base:        m.NewBase(HUMAN, o),
+		Base:        m.NewBase(HUMAN),
 		Persistence: NewPersistence(),
-		Point:       NewPoint(x, y),
+		Point:       *r.Point.Rand(m.conf.Residence.Randomize),
+		Mobility:    m.conf.Human.Speed,
+		Lifespan:    m.conf.Human.Lifespan.D.Seconds(),
 	}
 	h.Init(m)
-	h.Resolve()
+	h.Resolve(r, c)
 	h.Marshal()
 	m.Add(h)
 
@@ -77,8 +93,8 @@ func (m *Model) NewHuman(o *Player, x float64, y float64) *Human {
 func (h *Human) GenOutSteps() {
 	switch h.On {
 	case OnGround:
-		// h - C for destination
-		h.M.NewStep(h, h.To)
+		// h - C or R for destination
+		h.M.NewStep(h, h.Dest())
 		// h -> G
 		for _, g := range h.M.Gates {
 			h.M.NewStep(h, g)
@@ -87,7 +103,7 @@ func (h *Human) GenOutSteps() {
 		// h - G, P on Human
 		h.M.NewStep(h, h.onPlatform)
 		h.M.NewStep(h, h.onPlatform.WithGate)
-	case OnTrain:
+	case OnTrain, OnCompany:
 		// do-nothing
 	default:
 		panic(fmt.Errorf("invalid type: %T %+v", h.On, h.On))
@@ -109,10 +125,20 @@ func (h *Human) Pos() *Point {
 	return &h.Point
 }
 
+// Dest returns where Human heads for.
+func (h *Human) Dest() Relayable {
+	if h.Returning {
+		return h.From
+	}
+	return h.To
+}
+
 // Init creates map.
 func (h *Human) Init(m *Model) {
 	h.Base.Init(HUMAN, m)
 	h.M = m
+	h.On = OnGround
+	h.rode = make(map[uint]*Player)
 	h.out = make(map[uint]*Step)
 }
 
@@ -132,9 +158,13 @@ func (h *Human) Marshal() {
 	h.ToID = h.To.ID
 	if h.onPlatform != nil {
 		h.PlatformID = h.onPlatform.ID
+	} else {
+		h.PlatformID = ZERO
 	}
 	if h.onTrain != nil {
 		h.TrainID = h.onTrain.ID
+	} else {
+		h.TrainID = ZERO
 	}
 }
 
@@ -150,6 +180,9 @@ func (h *Human) UnMarshal() {
 	if h.TrainID != ZERO {
 		h.Resolve(h.M.Find(TRAIN, h.TrainID))
 	}
+	if h.On == OnGround && h.Work > 0 {
+		h.On = OnCompany
+	}
 }
 
 // Resolve set reference
@@ -164,9 +197,11 @@ func (h *Human) Resolve(args ...Entity) {
 			obj.Resolve(h)
 		case *Platform:
 			h.onPlatform = obj
+			h.On = OnPlatform
 			obj.Resolve(h)
 		case *Train:
 			h.onTrain = obj
+			h.On = OnTrain
 			obj.Resolve(h)
 		default:
 			panic(fmt.Errorf("invalid type: %T %+v", obj, obj))
@@ -180,12 +215,10 @@ func (h *Human) BeforeDelete() {
 	delete(h.From.Targets, h.ID)
 	delete(h.To.Targets, h.ID)
 	if h.onPlatform != nil {
-		h.onPlatform.Occupied--
-		delete(h.onPlatform.Passengers, h.ID)
+		h.UnResolve(h.onPlatform)
 	}
 	if h.onTrain != nil {
-		h.onTrain.Occupied--
-		delete(h.onTrain.Passengers, h.ID)
+		h.UnResolve(h.onTrain)
 	}
 }
 
@@ -193,6 +226,16 @@ func (h *Human) BeforeDelete() {
 func (h *Human) UnResolve(args ...interface{}) {
 	for _, raw := range args {
 		switch obj := raw.(type) {
+		case *Platform:
+			if h.onPlatform == obj {
+				h.onPlatform = nil
+				obj.UnResolve(h)
+			}
+		case *Train:
+			if h.onTrain == obj {
+				h.onTrain = nil
+				obj.UnResolve(h)
+			}
 		default:
 			panic(fmt.Errorf("invalid type: %T %+v", obj, obj))
 		}
@@ -207,6 +250,9 @@ func (h *Human) CheckDelete() error {
 
 // Delete removes this entity with related ones.
 func (h *Human) Delete() {
+	for _, s := range h.out {
+		h.M.Delete(s)
+	}
 	h.M.Delete(h)
 }
 
@@ -240,44 +286,233 @@ func (h *Human) WalkTo(dest Relayable) *Human {
 	return h
 }
 
+// Step makes Human follow Current Step with specified time.
+// Human works when it arrives at Company and is removed when it arrives at Residence or exhausts Lifespan.
+// Current becomes nil when Human arrives at Gate or Platform.
+// Human working in Company doesn't step; Labor progresses it instead.
+func (h *Human) Step(sec float64) {
+	h.Available = sec
+	if h.On == OnCompany {
+		return
+	}
+	h.Lifespan -= sec
+	if h.Lifespan <= 0 {
+		h.Delete()
+		return
+	}
+	if h.Current == nil {
+		return
+	}
+	switch h.On {
+	case OnGround:
+		dest := h.Current.ToNode
+		h.WalkTo(dest)
+		h.Change()
+		if h.Pos().Dist(dest.Pos()) > EPS {
+			return
+		}
+		switch obj := dest.(type) {
+		case *Company:
+			h.commute()
+			if h.M.conf.Human.Work.D > 0 {
+				h.Arrive(obj)
+			} else {
+				h.Delete()
+			}
+		case *Residence:
+			h.commute()
+			h.Delete()
+		case *Gate:
+			h.Enter(obj, obj.WithPlatform)
+		}
+	case OnPlatform:
+		// wait for Train when Current is Human -> Platform
+		if g, ok := h.Current.ToNode.(*Gate); ok {
+			h.Exit(h.onPlatform, g)
+		}
+	}
+}
+
+// commute rewards owners of Train Human rode on with time it took.
+func (h *Human) commute() {
+	for _, o := range h.rode {
+		o.Commute(h.M.conf.Human.Lifespan.D.Seconds() - h.Lifespan)
+	}
+}
+
+// Arrive makes Human work in Company.
+func (h *Human) Arrive(c *Company) *Human {
+	h.On = OnCompany
+	h.Point = c.Point
+	h.Work = h.M.conf.Human.Work.D.Seconds()
+	h.rode = make(map[uint]*Player)
+	h.Change()
+	h.resetOutSteps()
+	return h
+}
+
+// Labor makes Human working in Company progress its Work.
+// Human leaves Company when Work is finished.
+func (h *Human) Labor(sec float64) {
+	if h.On != OnCompany {
+		return
+	}
+	h.Work -= sec
+	if h.Work <= 0 {
+		h.Leave()
+	}
+}
+
+// Leave makes Human finish work and head for Residence.
+// Lifespan is renewed for return trip.
+func (h *Human) Leave() *Human {
+	h.On = OnGround
+	h.Work = 0
+	h.Returning = true
+	h.Lifespan = h.M.conf.Human.Lifespan.D.Seconds()
+	h.turnTo(h.From)
+	h.Change()
+	h.resetOutSteps()
+	return h
+}
+
+// GetIn makes Human on Platform ride on specified Train.
 func (h *Human) GetIn(t *Train) *Human {
-	//TODO
+	if h.onPlatform != nil {
+		h.boarding = h.onPlatform
+		h.UnResolve(h.onPlatform)
+	}
+	h.SetOnTrain(t)
+	h.rode[t.O.ID] = t.O
+	h.On = OnTrain
+	h.Point = t.Point
+	h.resetOutSteps()
 	return h
 }
 
+// GetOffForce makes Human get off Train on the spot.
+// It is called when Train is removed or undeployed.
 func (h *Human) GetOffForce() *Human {
-	//TODO
+	if t := h.onTrain; t != nil {
+		h.Point = *t.Point.Rand(h.M.conf.Train.Randomize)
+		h.UnResolve(t)
+	}
+	h.On = OnGround
+	h.Ride = nil
+	h.Change()
+	h.resetOutSteps()
 	return h
 }
 
+// GetOff makes Human get off Train at specified Platform.
 func (h *Human) GetOff(platform *Platform) *Human {
-	//TODO
+	if t := h.onTrain; t != nil {
+		if h.boarding != nil {
+			t.O.Carry(h.boarding.Pos().Dist(platform.Pos()))
+		}
+		h.UnResolve(t)
+	}
+	h.boarding = nil
+	h.SetOnPlatform(platform)
+	h.On = OnPlatform
+	h.Point = *platform.Pos().Rand(h.M.conf.Platform.Randomize)
+	h.Ride = nil
+	h.resetOutSteps()
 	return h
 }
 
+// Enter makes Human pass Gate and wait on Platform.
+// Human stays on ground when Platform is full.
 func (h *Human) Enter(from *Gate, to *Platform) *Human {
-	//TODO
+	if to.Occupied >= to.Capacity {
+		return h
+	}
+	h.SetOnPlatform(to)
+	h.On = OnPlatform
+	h.Point = *to.Pos().Rand(h.M.conf.Platform.Randomize)
+	h.resetOutSteps()
 	return h
 }
 
+// Exit makes Human leave Platform through Gate and head for destination.
 func (h *Human) Exit(from *Platform, to *Gate) *Human {
-	//TODO
+	h.UnResolve(from)
+	h.On = OnGround
+	h.Point = *to.Pos().Rand(h.M.conf.Platform.Randomize)
+	h.Ride = nil
+	h.turnTo(h.Dest())
+	h.Change()
+	h.resetOutSteps()
 	return h
 }
 
+// ExitForce makes Human leave Platform on the spot.
+// It is called when Platform is removed.
 func (h *Human) ExitForce() *Human {
-	//TODO
+	if p := h.onPlatform; p != nil {
+		h.UnResolve(p)
+	}
+	h.On = OnGround
+	h.Ride = nil
+	h.Change()
+	h.resetOutSteps()
 	return h
 }
 
+// ShouldGetIn returns whether Human waiting for Train should ride on it.
+// Human rides on Train running RailLine of next Transport when Train has vacancy.
 func (h *Human) ShouldGetIn(to *Train) bool {
-	// TODO
-	return false
+	if h.On != OnPlatform || to.task == nil || to.task.Stay != h.onPlatform {
+		return false
+	}
+	if to.Occupied >= to.Capacity {
+		return false
+	}
+	x := h.nextRide()
+	return x != nil && x.Via.RailLine == to.task.RailLine
 }
 
+// ShouldGetOff returns whether Human should get off Train at current Platform.
+// Human gets off at destination of Ride or next Platform when Human lost its Ride.
 func (h *Human) ShouldGetOff(from *Train) bool {
-	// TODO
-	return false
+	if h.onTrain != from || from.task == nil || from.task.TaskType != OnDeparture {
+		return false
+	}
+	return h.Ride == nil || h.Ride.ToPlatform == from.task.Stay
+}
+
+// nextRide returns Transport Human should take from Platform Human stands.
+// Ride specified by routing is prior to local estimation.
+// If walking to destination is faster than any Transport, it returns nil.
+func (h *Human) nextRide() *Transport {
+	p := h.onPlatform
+	if p == nil {
+		return nil
+	}
+	if h.Ride != nil && h.Ride.FromPlatform == p {
+		return h.Ride
+	}
+	h.Ride = nil
+	speed := h.M.conf.Human.Speed
+	dest := h.Dest().Pos()
+	min := p.Pos().Dist(dest) / speed
+	for _, x := range p.Transports {
+		// tie is broken by id to be independent of order of map
+		if v := x.Value + x.ToPlatform.Pos().Dist(dest)/speed; v < min || (v == min && h.Ride != nil && x.ID < h.Ride.ID) {
+			min = v
+			h.Ride = x
+		}
+	}
+	return h.Ride
+}
+
+// resetOutSteps regenerates Step from Human after Human moves to other place.
+func (h *Human) resetOutSteps() {
+	for _, s := range h.out {
+		h.M.Delete(s)
+	}
+	h.Current = nil
+	h.GenOutSteps()
 }
 
 // OnPlatform return platform human stands
@@ -289,6 +524,7 @@ func (h *Human) OnPlatform() *Platform {
 func (h *Human) SetOnPlatform(v *Platform) {
 	h.onPlatform = v
 	v.Resolve(h)
+	h.Marshal()
 	h.Change()
 }
 
@@ -301,6 +537,7 @@ func (h *Human) OnTrain() *Train {
 func (h *Human) SetOnTrain(v *Train) {
 	h.onTrain = v
 	v.Resolve(h)
+	h.Marshal()
 	h.Change()
 }
 
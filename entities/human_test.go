@@ -0,0 +1,331 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yasshi2525/RushHour/auth"
+	"github.com/yasshi2525/RushHour/config"
+)
+
+func TestHuman(t *testing.T) {
+	a, _ := auth.GetAuther(config.CnfAuth{Key: "----------------"})
+	c := config.CnfEntity{
+		MaxScale:  16,
+		Residence: config.CnfResidence{Capacity: 1},
+		Platform:  config.CnfPlatform{Capacity: 1},
+		Train:     config.CnfTrain{Speed: 10, Capacity: 1, Mobility: 1, Slowness: 0.5},
+		Human:     config.CnfHuman{Speed: 1},
+	}
+	c.Human.Lifespan.D = 1000 * time.Second
+
+	// prepare builds r -> (p0) ======= (p1) -> c with one Train
+	prepare := func() (*Model, *Residence, *Company, *Platform, *Platform, *Train) {
+		m := NewModel(c, a)
+		o := m.NewPlayer()
+		r := m.NewResidence(0, 0)
+		cp := m.NewCompany(200, 0)
+		n0 := m.NewRailNode(o, 0, 0)
+		n1, e01 := n0.Extend(200, 0)
+		st0, st1 := m.NewStation(o), m.NewStation(o)
+		p0 := m.NewPlatform(n0, m.NewGate(st0))
+		p1 := m.NewPlatform(n1, m.NewGate(st1))
+
+		l := m.NewRailLine(o)
+		head := m.NewLineTaskDept(l, p0)
+		tail := m.NewLineTask(l, e01, head)
+		tail = m.NewLineTaskDept(l, p1, tail)
+		tail = m.NewLineTask(l, e01.Reverse, tail)
+		tail.SetNext(head)
+
+		m.NewTransport(p0, p1, head, 10)
+		tr := m.NewTrain(o, "test")
+		tr.SetTask(head)
+		return m, r, cp, p0, p1, tr
+	}
+
+	t.Run("NewHuman", func(t *testing.T) {
+		m, r, cp, _, _, _ := prepare()
+		h := m.NewHuman(r, cp)
+
+		TestCases{
+			{"r", h.From, r},
+			{"c", h.To, cp},
+			{"r.h", r.Targets[h.ID], h},
+			{"c.h", cp.Targets[h.ID], h},
+			{"on", h.On, OnGround},
+			// C, G0, G1
+			{"out", len(h.OutSteps()), 3},
+			{"model", m.Humans[h.ID], h},
+		}.Assert(t)
+	})
+
+	t.Run("Enter", func(t *testing.T) {
+		m, r, cp, p0, _, _ := prepare()
+		h := m.NewHuman(r, cp)
+		oth := m.NewHuman(r, cp)
+		h.Enter(p0.WithGate, p0)
+		oth.Enter(p0.WithGate, p0)
+
+		TestCases{
+			{"on", h.On, OnPlatform},
+			{"p", h.OnPlatform(), p0},
+			{"pid", h.PlatformID, p0.ID},
+			{"p.h", p0.Passengers[h.ID], h},
+			{"out", len(h.OutSteps()), 2},
+			{"occupied", p0.Occupied, 1},
+			{"full", oth.On, OnGround},
+		}.Assert(t)
+	})
+
+	t.Run("Exit", func(t *testing.T) {
+		m, r, cp, p0, _, _ := prepare()
+		h := m.NewHuman(r, cp)
+		h.Enter(p0.WithGate, p0)
+		h.Exit(p0, p0.WithGate)
+
+		TestCases{
+			{"on", h.On, OnGround},
+			{"p", h.OnPlatform(), (*Platform)(nil)},
+			{"pid", h.PlatformID, ZERO},
+			{"occupied", p0.Occupied, 0},
+			{"out", len(h.OutSteps()), 3},
+		}.Assert(t)
+	})
+
+	t.Run("ShouldGetIn", func(t *testing.T) {
+		m, r, cp, p0, p1, tr := prepare()
+		h := m.NewHuman(r, cp)
+
+		TestCases{
+			{"ground", h.ShouldGetIn(tr), false},
+		}.Assert(t)
+
+		h.Enter(p0.WithGate, p0)
+
+		TestCases{
+			{"platform", h.ShouldGetIn(tr), true},
+			{"ride", h.Ride, p0.Transports[p1.ID]},
+		}.Assert(t)
+	})
+
+	t.Run("Step", func(t *testing.T) {
+		m, r, cp, p0, p1, tr := prepare()
+		h := m.NewHuman(r, cp)
+		h.Enter(p0.WithGate, p0)
+
+		tr.Step(0.5)
+
+		TestCases{
+			{"waiting", h.On, OnPlatform},
+		}.Assert(t)
+
+		tr.Step(0.5)
+
+		TestCases{
+			{"on", h.On, OnTrain},
+			{"t", h.OnTrain(), tr},
+			{"tid", h.TrainID, tr.ID},
+			{"t.occupied", tr.Occupied, 1},
+			{"p.occupied", p0.Occupied, 0},
+			{"out", len(h.OutSteps()), 0},
+		}.Assert(t)
+
+		for tr.Task().TaskType != OnDeparture || tr.Task().Stay != p1 {
+			tr.Step(1)
+		}
+		tr.Step(1)
+
+		TestCases{
+			{"on", h.On, OnPlatform},
+			{"p", h.OnPlatform(), p1},
+			{"t.occupied", tr.Occupied, 0},
+			{"p.occupied", p1.Occupied, 1},
+			{"carried", tr.O.Carried, 1},
+			{"carried dist", tr.O.CarriedDist, 200.0},
+			{"delivered", tr.O.Delivered, int64(0)},
+		}.Assert(t)
+	})
+
+	t.Run("Walk", func(t *testing.T) {
+		t.Run("company", func(t *testing.T) {
+			m, r, cp, _, _, tr := prepare()
+			h := m.NewHuman(r, cp)
+			h.rode[tr.O.ID] = tr.O
+			for _, s := range h.OutSteps() {
+				if s.ToNode == cp {
+					h.Current = s
+				}
+			}
+			h.Step(100)
+
+			TestCases{
+				{"x", h.X, 100.0},
+				{"available", h.Available, 0.0},
+				{"lifespan", h.Lifespan, 900.0},
+				{"model", len(m.Humans), 1},
+			}.Assert(t)
+
+			h.Step(100)
+
+			TestCases{
+				{"model", len(m.Humans), 0},
+				{"c.h", len(cp.Targets), 0},
+				{"delivered", tr.O.Delivered, int64(1)},
+			}.Assert(t)
+		})
+		t.Run("return", func(t *testing.T) {
+			c := c
+			c.Human.Work.D = 50 * time.Second
+			m := NewModel(c, a)
+			r := m.NewResidence(0, 0)
+			cp := m.NewCompany(100, 0)
+			h := m.NewHuman(r, cp)
+			for _, s := range h.OutSteps() {
+				if s.ToNode == cp {
+					h.Current = s
+				}
+			}
+			h.Step(100)
+
+			TestCases{
+				{"on", h.On, OnCompany},
+				{"work", h.Work, 50.0},
+				{"out", len(h.OutSteps()), 0},
+				{"dest", h.Dest(), cp},
+				{"model", len(m.Humans), 1},
+			}.Assert(t)
+
+			// work progresses by Labor only
+			h.Step(50)
+
+			TestCases{
+				{"step", h.Work, 50.0},
+			}.Assert(t)
+
+			h.Labor(50)
+
+			TestCases{
+				{"on", h.On, OnGround},
+				{"returning", h.Returning, true},
+				{"lifespan", h.Lifespan, 1000.0},
+				{"dest", h.Dest(), r},
+				{"out", len(h.OutSteps()), 1},
+			}.Assert(t)
+
+			for _, s := range h.OutSteps() {
+				h.Current = s
+			}
+			h.Step(100)
+
+			TestCases{
+				{"model", len(m.Humans), 0},
+				{"r.h", len(r.Targets), 0},
+				{"c.h", len(cp.Targets), 0},
+			}.Assert(t)
+		})
+		t.Run("labor", func(t *testing.T) {
+			c := c
+			c.Human.Work.D = 50 * time.Second
+			m := NewModel(c, a)
+			r := m.NewResidence(0, 0)
+			cp := m.NewCompany(100, 0)
+			h := m.NewHuman(r, cp)
+			h.Labor(50)
+
+			TestCases{
+				{"walking", h.On, OnGround},
+			}.Assert(t)
+
+			h.Arrive(cp)
+			h.Labor(30)
+
+			TestCases{
+				{"on", h.On, OnCompany},
+				{"work", h.Work, 20.0},
+			}.Assert(t)
+
+			h.Labor(20)
+
+			TestCases{
+				{"on", h.On, OnGround},
+				{"returning", h.Returning, true},
+			}.Assert(t)
+		})
+		t.Run("gate", func(t *testing.T) {
+			m, r, cp, _, p1, _ := prepare()
+			h := m.NewHuman(r, cp)
+			for _, s := range h.OutSteps() {
+				if s.ToNode == p1.WithGate {
+					h.Current = s
+				}
+			}
+			h.Step(300)
+
+			TestCases{
+				{"on", h.On, OnPlatform},
+				{"p", h.OnPlatform(), p1},
+				{"current", h.Current, (*Step)(nil)},
+				{"available", h.Available, 100.0},
+			}.Assert(t)
+		})
+		t.Run("exit", func(t *testing.T) {
+			m, r, cp, _, p1, _ := prepare()
+			h := m.NewHuman(r, cp)
+			h.Enter(p1.WithGate, p1)
+			for _, s := range h.OutSteps() {
+				if s.ToNode == p1.WithGate {
+					h.Current = s
+				}
+			}
+			h.Step(1)
+
+			TestCases{
+				{"on", h.On, OnGround},
+				{"p.occupied", p1.Occupied, 0},
+				{"current", h.Current, (*Step)(nil)},
+			}.Assert(t)
+		})
+		t.Run("lifespan", func(t *testing.T) {
+			m, r, cp, _, _, _ := prepare()
+			h := m.NewHuman(r, cp)
+			h.Step(1000)
+
+			TestCases{
+				{"model", len(m.Humans), 0},
+				{"r.h", len(r.Targets), 0},
+			}.Assert(t)
+		})
+	})
+
+	t.Run("GetOffForce", func(t *testing.T) {
+		m, r, cp, p0, _, tr := prepare()
+		h := m.NewHuman(r, cp)
+		h.Enter(p0.WithGate, p0)
+		h.GetIn(tr)
+		tr.SetTask(nil)
+
+		TestCases{
+			{"on", h.On, OnGround},
+			{"t", h.OnTrain(), (*Train)(nil)},
+			{"t.occupied", tr.Occupied, 0},
+			{"out", len(h.OutSteps()), 3},
+		}.Assert(t)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		m, r, cp, p0, _, _ := prepare()
+		h := m.NewHuman(r, cp)
+		h.Enter(p0.WithGate, p0)
+		steps := len(m.Steps)
+		h.Delete()
+
+		TestCases{
+			{"r.h", len(r.Targets), 0},
+			{"c.h", len(cp.Targets), 0},
+			{"p.occupied", p0.Occupied, 0},
+			{"steps", len(m.Steps), steps - 2},
+			{"model", len(m.Humans), 0},
+		}.Assert(t)
+	})
+}
@@ -0,0 +1,65 @@
+package entities
+
+// Journal records entities affecting route of Human since last routing.
+// Router consumes it in order to update route incrementally.
+// Step of Human is out of target because Human is routed on demand.
+type Journal struct {
+	// Added is Relayable and Connectable created after last routing.
+	Added map[ModelType]map[uint]Entity
+	// Removed is Relayable and Connectable deleted after last routing.
+	Removed map[ModelType]map[uint]bool
+}
+
+// NewJournal creates empty instance.
+func NewJournal() *Journal {
+	j := &Journal{
+		Added:   make(map[ModelType]map[uint]Entity),
+		Removed: make(map[ModelType]map[uint]bool),
+	}
+	for _, res := range journalTypes {
+		j.Added[res] = make(map[uint]Entity)
+		j.Removed[res] = make(map[uint]bool)
+	}
+	return j
+}
+
+// journalTypes is the list of types composing route of Human.
+var journalTypes = []ModelType{RESIDENCE, COMPANY, GATE, PLATFORM, STEP, TRANSPORT}
+
+// IsEmpty returns whether nothing is changed or not.
+func (j *Journal) IsEmpty() bool {
+	for _, res := range journalTypes {
+		if len(j.Added[res]) > 0 || len(j.Removed[res]) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// record registers created or deleted entity.
+// Entity created and deleted after last routing is forgotten.
+func (j *Journal) record(obj Entity, added bool) {
+	res, id := obj.B().Type(), obj.B().Idx()
+	if _, ok := j.Added[res]; !ok {
+		return
+	}
+	if s, ok := obj.(*Step); ok {
+		if _, ok := s.FromNode.(*Human); ok {
+			return
+		}
+	}
+	if added {
+		j.Added[res][id] = obj
+	} else if _, ok := j.Added[res][id]; ok {
+		delete(j.Added[res], id)
+	} else {
+		j.Removed[res][id] = true
+	}
+}
+
+// TakeJournal returns changes since last call and starts new recording.
+func (m *Model) TakeJournal() *Journal {
+	j := m.journal
+	m.journal = NewJournal()
+	return j
+}
@@ -0,0 +1,190 @@
+package entities
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+)
+
+// MapView is read-only copy of Cluster tree whose delegates are serialized.
+// It is not changed after copied, so it is viewed without lock of Model.
+type MapView struct {
+	root       *clusterView
+	tombstones []*Tombstone
+	horizon    time.Time
+	maxScale   int
+	// CopiedAt is when Cluster tree is copied
+	CopiedAt time.Time
+}
+
+// clusterView is copy of Cluster.
+type clusterView struct {
+	ChunkPoint
+	changedAt time.Time
+	chunks    []*chunkView
+	children  [2][2]*clusterView
+}
+
+// chunkView is delegates which Chunk exports.
+type chunkView struct {
+	changedAt time.Time
+	delegates []*delegateView
+}
+
+// delegateView is serialized delegate.
+type delegateView struct {
+	t         ModelType
+	id        uint
+	changedAt time.Time
+	data      json.RawMessage
+}
+
+// DelegateCache keeps serialized delegates in order to reuse ones not changed since previous copy.
+type DelegateCache map[delegateLocalable]*delegateView
+
+// delegateTypeList is the list of delegated resources in the order of fields of DelegateMap
+var delegateTypeList = []ModelType{RESIDENCE, COMPANY, RAILNODE, RAILEDGE, PLATFORM, TRAIN, LINETASK}
+
+// CopyMap returns MapView of current Cluster tree.
+// Delegates in cache not changed since then are not serialized again.
+// Returned cache has only delegates over MapView and it should be passed to next copy.
+func (m *Model) CopyMap(cache DelegateCache) (*MapView, DelegateCache) {
+	next := make(DelegateCache)
+	mv := &MapView{
+		root:       m.RootCluster.copyView(cache, next),
+		tombstones: m.Tombstones[:len(m.Tombstones):len(m.Tombstones)],
+		horizon:    m.TombstoneHorizon,
+		maxScale:   m.conf.MaxScale,
+		CopiedAt:   time.Now(),
+	}
+	return mv, next
+}
+
+func (cl *Cluster) copyView(cache DelegateCache, next DelegateCache) *clusterView {
+	cv := &clusterView{ChunkPoint: cl.ChunkPoint, changedAt: cl.ChangedAt}
+	for _, ch := range cl.Data {
+		dm := &DelegateMap{}
+		dm.Init()
+		ch.Export(dm)
+		chv := &chunkView{changedAt: ch.ChangedAt}
+		for _, t := range delegateTypeList {
+			v := dm.Values[t]
+			for _, key := range v.MapKeys() {
+				if dv := cache.serialize(v.MapIndex(key).Interface().(delegateLocalable), next); dv != nil {
+					chv.delegates = append(chv.delegates, dv)
+				}
+			}
+		}
+		cv.chunks = append(cv.chunks, chv)
+	}
+	for dy, list := range cl.Children {
+		for dx, child := range list {
+			if child != nil {
+				cv.children[dy][dx] = child.copyView(cache, next)
+			}
+		}
+	}
+	return cv
+}
+
+// serialize returns serialized delegate and registers it to next.
+// It returns nil when delegate fails to be serialized.
+func (cache DelegateCache) serialize(obj delegateLocalable, next DelegateCache) *delegateView {
+	if dv, ok := next[obj]; ok {
+		return dv
+	}
+	if dv, ok := cache[obj]; ok && dv.changedAt.Equal(obj.B().ChangedAt) {
+		next[obj] = dv
+		return dv
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		log.Printf("failed to marshal %v: %v", obj, err)
+		return nil
+	}
+	dv := &delegateView{obj.B().Type(), obj.B().Idx(), obj.B().ChangedAt, data}
+	next[obj] = dv
+	return dv
+}
+
+// ViewMap returns serialized delegates over viewport in the same way as Cluster.ViewMap.
+// It returns whole map when since is zero or removal after since has been already pruned.
+// Otherwise it returns delegates changed at since or later and id of removed ones.
+func (mv *MapView) ViewMap(pos *ChunkPoint, span int, since time.Time) *MapData {
+	md := &MapData{
+		Delegates: make(map[ModelType]map[uint]json.RawMessage),
+		Deletes:   make(map[string][]uint),
+		Timestamp: mv.CopiedAt.Unix(),
+	}
+	for _, t := range delegateTypeList {
+		md.Delegates[t] = make(map[uint]json.RawMessage)
+	}
+	if !since.IsZero() && !since.Before(mv.horizon) {
+		md.Since, md.Delta = since, true
+	}
+	mv.root.viewMap(md, pos, span)
+	if md.Delta {
+		for _, tb := range mv.tombstones {
+			if tb.exports(pos, span, mv.maxScale) && !tb.DeletedAt.Before(md.Since) {
+				md.Deletes[tb.Type.API()] = append(md.Deletes[tb.Type.API()], tb.ID)
+			}
+		}
+	}
+	return md
+}
+
+func (cv *clusterView) viewMap(md *MapData, pos *ChunkPoint, span int) {
+	if cv.changedAt.Before(md.Since) || !cv.ChunkPoint.contains(pos) {
+		return
+	}
+	if cv.Scale <= pos.Scale-span {
+		for _, chv := range cv.chunks {
+			if chv.changedAt.Before(md.Since) {
+				continue
+			}
+			for _, dv := range chv.delegates {
+				if !dv.changedAt.Before(md.Since) {
+					md.Delegates[dv.t][dv.id] = dv.data
+				}
+			}
+		}
+		return
+	}
+	for _, list := range cv.children {
+		for _, child := range list {
+			if child != nil {
+				child.viewMap(md, pos, span)
+			}
+		}
+	}
+}
+
+// MapData is serialized delegates over viewport.
+// It is marshaled in the same form as DelegateMap.
+type MapData struct {
+	// Delegates is serialized delegates for each type
+	Delegates map[ModelType]map[uint]json.RawMessage
+	// Deletes is the list of delegated id removed after Since
+	Deletes map[string][]uint
+	// Delta is true when map contains only delegates changed after Since
+	Delta bool
+	// Since is the lower limit of ChangedAt. Zero value means whole map.
+	Since time.Time
+
+	Timestamp int64
+}
+
+// MarshalJSON represents delegates keyed by name of resource
+func (md *MapData) MarshalJSON() ([]byte, error) {
+	obj := map[string]interface{}{
+		"delta":     md.Delta,
+		"timestamp": md.Timestamp,
+	}
+	for _, t := range delegateTypeList {
+		obj[t.API()] = md.Delegates[t]
+	}
+	if len(md.Deletes) > 0 {
+		obj["deletes"] = md.Deletes
+	}
+	return json.Marshal(obj)
+}
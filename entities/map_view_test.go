@@ -0,0 +1,78 @@
+package entities
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/yasshi2525/RushHour/auth"
+	"github.com/yasshi2525/RushHour/config"
+)
+
+func TestMapView(t *testing.T) {
+	a, _ := auth.GetAuther(config.CnfAuth{Key: "----------------"})
+	t.Run("ViewMap", func(t *testing.T) {
+		m := NewModel(config.CnfEntity{
+			MaxScale: 1,
+		}, a)
+		pos := &ChunkPoint{Scale: m.conf.MaxScale}
+		r := m.NewResidence(0, 0)
+		dr := m.RootCluster.Data[ZERO].Residence
+		mv, cache := m.CopyMap(nil)
+		since := time.Now()
+		c := m.NewCompany(0, 0)
+		dc := m.RootCluster.Data[ZERO].Company
+
+		dm := &DelegateMap{}
+		dm.Init()
+		m.RootCluster.ViewMap(dm, pos, 0)
+		want, _ := json.Marshal(dm.Residences[dr.ID])
+		md := mv.ViewMap(pos, 0, time.Time{})
+
+		TestCases{
+			{"r", string(md.Delegates[RESIDENCE][dr.ID]), string(want)},
+			// copy isn't changed after Model changes
+			{"c", len(md.Delegates[COMPANY]), 0},
+			{"delta", md.Delta, false},
+		}.Assert(t)
+
+		next, cache := m.CopyMap(cache)
+		md = next.ViewMap(pos, 0, since)
+		TestCases{
+			{"since.r", len(md.Delegates[RESIDENCE]), 0},
+			{"since.c", len(md.Delegates[COMPANY][dc.ID]) > 0, true},
+			{"since.delta", md.Delta, true},
+			// unchanged delegate is not serialized again
+			{"cache", &cache[dr].data[0], &mv.root.chunks[0].delegates[0].data[0]},
+		}.Assert(t)
+
+		since = time.Now()
+		c.Delete()
+		r.Delete()
+		mv, _ = m.CopyMap(cache)
+		md = mv.ViewMap(pos, 0, since)
+		data, _ := json.Marshal(md)
+		var out map[string]interface{}
+		json.Unmarshal(data, &out)
+
+		TestCases{
+			{"deletes.r", len(md.Deletes[RESIDENCE.API()]), 1},
+			{"deletes.c", len(md.Deletes[COMPANY.API()]), 1},
+			{"json.companies", len(out["companies"].(map[string]interface{})), 0},
+			{"json.deletes", len(out["deletes"].(map[string]interface{})), 2},
+			{"json.delta", out["delta"], true},
+		}.Assert(t)
+
+		m.PruneTombstones(time.Now())
+		md = mv.ViewMap(pos, 0, since)
+		TestCases{
+			// copy keeps tombstones when it was copied
+			{"copied.deletes", len(md.Deletes[COMPANY.API()]), 1},
+		}.Assert(t)
+		mv, _ = m.CopyMap(nil)
+		md = mv.ViewMap(pos, 0, since)
+		TestCases{
+			{"pruned.delta", md.Delta, false},
+		}.Assert(t)
+	})
+}
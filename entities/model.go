@@ -3,7 +3,9 @@ package entities
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"sync/atomic"
+	"time"
 
 	"github.com/yasshi2525/RushHour/auth"
 	"github.com/yasshi2525/RushHour/config"
@@ -38,6 +40,14 @@ type Model struct {
 	NextIDs map[ModelType]*uint64
 	// Deletes represents the list of deleting in next Backup()
 	Deletes map[ModelType][]uint
+	// Tombstones represents the list of removed delegates ordered by DeletedAt
+	Tombstones []*Tombstone
+	// TombstoneHorizon represents Tombstones before it have been already pruned
+	TombstoneHorizon time.Time
+	// journal represents changes of route since last routing
+	journal *Journal
+	// sorted represents id of each type in ascending order
+	sorted map[ModelType]*idIndex
 
 	// Map represents each resource map
 	Values map[ModelType]reflect.Value
@@ -64,6 +74,18 @@ func (m *Model) ForEach(res ModelType, callback func(Entity)) {
 	}
 }
 
+// ForEachSorted executes callback for each entity specified type in order of id.
+// Simulation iterates entities in this order to be reproducible with the same seed.
+// Entity removed by callback before its turn is skipped.
+func (m *Model) ForEachSorted(res ModelType, callback func(Entity)) {
+	mapdata := m.Values[res]
+	for _, id := range m.SortedIDs(res) {
+		if obj := mapdata.MapIndex(reflect.ValueOf(id)); obj.IsValid() {
+			callback(obj.Interface().(Entity))
+		}
+	}
+}
+
 // GenID generates unique id. This is thread-safe.
 func (m *Model) GenID(res ModelType) uint {
 	return uint(atomic.AddUint64(m.NextIDs[res], 1))
@@ -76,6 +98,8 @@ func (m *Model) Add(args ...Entity) {
 			reflect.ValueOf(obj.B().Idx()),
 			reflect.ValueOf(obj))
 		m.RootCluster.Add(obj)
+		m.journal.record(obj, true)
+		m.sorted[obj.B().Type()].add(obj.B().Idx())
 	}
 }
 
@@ -115,6 +139,39 @@ func (m *Model) Delete(args ...Entity) {
 			m.Deletes[obj.B().Type()] = append(m.Deletes[obj.B().Type()], obj.B().Idx())
 		}
 		m.RootCluster.Remove(obj)
+		m.journal.record(obj, false)
+		m.sorted[obj.B().Type()].stale++
+	}
+}
+
+// bury records removal of delegate object over Chunk.
+func (m *Model) bury(ch *Chunk, res ModelType, id uint) {
+	m.Tombstones = append(m.Tombstones, &Tombstone{
+		ChunkPoint: ch.ChunkPoint,
+		Type:       res,
+		ID:         id,
+		DeletedAt:  time.Now(),
+	})
+}
+
+// ViewTombstones set removed delegate id to DelegateMap.
+// It targets the same Chunks as Cluster.ViewMap does.
+func (m *Model) ViewTombstones(dm *DelegateMap, pos *ChunkPoint, span int) {
+	for _, tb := range m.Tombstones {
+		if tb.exports(pos, span, m.conf.MaxScale) {
+			dm.Bury(tb)
+		}
+	}
+}
+
+// PruneTombstones removes Tombstones before specified time.
+func (m *Model) PruneTombstones(before time.Time) {
+	idx := sort.Search(len(m.Tombstones), func(i int) bool {
+		return !m.Tombstones[i].DeletedAt.Before(before)
+	})
+	m.Tombstones = m.Tombstones[idx:]
+	if m.TombstoneHorizon.Before(before) {
+		m.TombstoneHorizon = before
 	}
 }
 
@@ -129,6 +186,45 @@ func (m *Model) Ids(res ModelType) []uint {
 	return ids
 }
 
+// SortedIDs returns list of id specified type in ascending order.
+// It must not be modified because it is shared until next Add or Delete.
+func (m *Model) SortedIDs(res ModelType) []uint {
+	idx, mapdata := m.sorted[res], m.Values[res]
+	if idx.stale > 0 {
+		// make new slice because caller may iterate previous one
+		ids := make([]uint, 0, mapdata.Len())
+		for _, id := range idx.ids {
+			if mapdata.MapIndex(reflect.ValueOf(id)).IsValid() {
+				ids = append(ids, id)
+			}
+		}
+		idx.ids, idx.stale = ids, 0
+	}
+	if len(idx.ids) != mapdata.Len() {
+		// entities were registered without Add such as Restore
+		idx.ids = m.Ids(res)
+		sort.Slice(idx.ids, func(i, j int) bool { return idx.ids[i] < idx.ids[j] })
+	}
+	return idx.ids
+}
+
+// idIndex is id of entities in ascending order.
+// Removed id is dropped at next read instead of shifting slice on each Delete.
+type idIndex struct {
+	ids []uint
+	// stale is the number of Delete since last read
+	stale int
+}
+
+// add appends id generated after others. Otherwise it makes index rebuilt at next read.
+func (idx *idIndex) add(id uint) {
+	if n := len(idx.ids); n == 0 || idx.ids[n-1] < id {
+		idx.ids = append(idx.ids, id)
+	} else {
+		idx.ids = nil
+	}
+}
+
 // Len returns the number of all type of objects.
 func (m *Model) Len() int {
 	var sum int
@@ -193,6 +289,7 @@ func NewModel(conf config.CnfEntity, a *auth.Auther) *Model {
 	obj.NextIDs = make(map[ModelType]*uint64)
 	obj.Deletes = make(map[ModelType][]uint)
 	obj.Values = make(map[ModelType]reflect.Value)
+	obj.sorted = make(map[ModelType]*idIndex)
 
 	// set slice to specific fields
 	for idx, res := range TypeList {
@@ -204,12 +301,16 @@ func NewModel(conf config.CnfEntity, a *auth.Auther) *Model {
 			obj.Deletes[res] = []uint{}
 		}
 		obj.Values[res] = model.Field(idx)
+		obj.sorted[res] = &idIndex{}
 	}
 
 	obj.Logins = make(map[AuthType]map[string]*Player)
 	for _, auth := range AuthList {
 		obj.Logins[auth] = make(map[string]*Player)
 	}
+	obj.Tombstones = []*Tombstone{}
+	obj.TombstoneHorizon = time.Now()
+	obj.journal = NewJournal()
 	obj.conf = conf
 	obj.RootCluster = obj.NewCluster(nil, 0, 0)
 	obj.auther = a
@@ -0,0 +1,41 @@
+package entities
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/yasshi2525/RushHour/auth"
+	"github.com/yasshi2525/RushHour/config"
+)
+
+func TestModel(t *testing.T) {
+	a, _ := auth.GetAuther(config.CnfAuth{Key: "----------------"})
+	c := config.CnfEntity{MaxScale: 16}
+	t.Run("SortedIDs", func(t *testing.T) {
+		m := NewModel(c, a)
+		c1, c2, c3 := m.NewCompany(0, 0), m.NewCompany(1, 1), m.NewCompany(2, 2)
+		before := m.SortedIDs(COMPANY)
+		c2.Delete()
+		c4 := m.NewCompany(3, 3)
+
+		// registered without Add as Restore does
+		c5 := &Company{Base: m.NewBase(COMPANY)}
+		m.Companies[c5.ID] = c5
+
+		TestCases{
+			{"before", fmt.Sprint(before), fmt.Sprint([]uint{c1.ID, c2.ID, c3.ID})},
+			{"after", fmt.Sprint(m.SortedIDs(COMPANY)), fmt.Sprint([]uint{c1.ID, c3.ID, c4.ID, c5.ID})},
+		}.Assert(t)
+
+		var ids []uint
+		m.ForEachSorted(COMPANY, func(obj Entity) {
+			ids = append(ids, obj.B().Idx())
+			if obj == c1 {
+				c3.Delete()
+			}
+		})
+		TestCases{
+			{"skip removed", fmt.Sprint(ids), fmt.Sprint([]uint{c1.ID, c4.ID, c5.ID})},
+		}.Assert(t)
+	})
+}
@@ -152,6 +152,11 @@ func (p *Platform) Resolve(args ...Entity) {
 func (p *Platform) UnResolve(args ...Entity) {
 	for _, raw := range args {
 		switch obj := raw.(type) {
+		case *Human:
+			if _, ok := p.Passengers[obj.ID]; ok {
+				delete(p.Passengers, obj.ID)
+				p.Occupied--
+			}
 		case *LineTask:
 			switch obj.TaskType {
 			case OnDeparture:
@@ -204,7 +209,7 @@ func (p *Platform) CheckDelete() error {
 // BeforeDelete delete related reference.
 func (p *Platform) BeforeDelete() {
 	for _, h := range p.Passengers {
-		h.UnResolve(p)
+		h.ExitForce()
 	}
 	for _, t := range p.Trains {
 		t.UnResolve(p)
@@ -231,6 +236,11 @@ func (p *Platform) Delete() {
 	for _, s := range p.inSteps {
 		p.M.Delete(s)
 	}
+	for _, x := range p.M.Transports {
+		if x.FromPlatform == p || x.ToPlatform == p {
+			p.M.Delete(x)
+		}
+	}
 	p.M.Delete(p)
 }
 
@@ -84,7 +84,24 @@ type Player struct {
 	// OAuthSecret is hidden attribute and used for OAuth authentication (access token secret).
 	OAuthSecret string `gorm:"not null" sql:"type:text" json:"-"`
 
-	ReRouting bool `gorm:"-" json:"-"`
+	// ReRouting is true while tracks of Player are waiting for refresh.
+	ReRouting bool `gorm:"-" json:"rerouting"`
+
+	// Money represents how much Player can spend for construction.
+	Money int64 `gorm:"not null" json:"money"`
+	// Carried represents how many Human got off Train of Player since last settlement.
+	Carried int `gorm:"-" json:"-"`
+	// CarriedDist represents how long Human rode on Train of Player since last settlement.
+	CarriedDist float64 `gorm:"-" json:"-"`
+
+	// Delivered represents total number of Human delivered to Company by Train of Player.
+	Delivered int64 `gorm:"not null" json:"delivered"`
+	// Commuters represents total number of Human arrived at Company by Train of Player.
+	Commuters int64 `gorm:"not null" json:"-"`
+	// CommuteTime represents total seconds Commuters spent from Residence to Company.
+	CommuteTime float64 `gorm:"not null" json:"-"`
+	// Revenue represents total fare Player earned.
+	Revenue int64 `gorm:"not null" json:"revenue"`
 
 	// Hue is hue attribute on HSV model.
 	Hue int `gorm:"not null" json:"hue"`
@@ -179,6 +196,59 @@ func (o *Player) P() *Persistence {
 	return &o.Persistence
 }
 
+// CanPay returns error when Player doesn't have enough money.
+func (o *Player) CanPay(cost int64) error {
+	if o.Money < cost {
+		return fmt.Errorf("insufficient funds: %d < %d", o.Money, cost)
+	}
+	return nil
+}
+
+// Pay subtracts cost from Money.
+func (o *Player) Pay(cost int64) {
+	o.Money -= cost
+	o.Change()
+}
+
+// Earn adds revenue to Money.
+func (o *Player) Earn(revenue int64) {
+	o.Money += revenue
+	o.Revenue += revenue
+	o.Change()
+}
+
+// Carry records Human rode on Train of Player for specified distance.
+func (o *Player) Carry(dist float64) {
+	o.Carried++
+	o.CarriedDist += dist
+	o.Change()
+}
+
+// Commute records Human arrived at Company by Train of Player.
+func (o *Player) Commute(sec float64) {
+	o.Delivered++
+	o.Commuters++
+	o.CommuteTime += sec
+	o.Change()
+}
+
+// NetworkLength returns total length of rail Player owns.
+func (o *Player) NetworkLength() float64 {
+	var sum float64
+	for _, re := range o.RailEdges {
+		sum += re.FromNode.Point.Dist(&re.ToNode.Point)
+	}
+	// RailEdge is always paired with its Reverse
+	return sum / 2
+}
+
+// Settle returns the number and the distance of Human carried since last settlement.
+func (o *Player) Settle() (int, float64) {
+	num, dist := o.Carried, o.CarriedDist
+	o.Carried, o.CarriedDist = 0, 0
+	return num, dist
+}
+
 // ClearTracks eraces track infomation.
 func (o *Player) ClearTracks() {
 	for _, rn := range o.RailNodes {
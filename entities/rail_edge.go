@@ -65,7 +65,13 @@ func (re *RailEdge) To() Entity {
 
 // Cost represents distance
 func (re *RailEdge) Cost() float64 {
-	return re.FromNode.Point.Dist(&re.ToNode.Point) / re.M.conf.Train.Speed
+	return re.M.RailCost(&re.FromNode.Point, &re.ToNode.Point)
+}
+
+// RailCost returns Cost of RailEdge between two points.
+// It is also used to estimate RailEdge before creating it.
+func (m *Model) RailCost(from *Point, to *Point) float64 {
+	return from.Dist(to) / m.conf.Train.Speed
 }
 
 // Div returns dividing point to certain ratio.
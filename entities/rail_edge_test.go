@@ -34,6 +34,19 @@ func TestRailEdge(t *testing.T) {
 			{"reroute", o.ReRouting, true},
 		}.Assert(t)
 	})
+	t.Run("Cost", func(t *testing.T) {
+		c := c
+		c.Train.Speed = 2
+		m := NewModel(c, a)
+		o := m.NewPlayer()
+		n1 := m.NewRailNode(o, 0, 0)
+		_, re := n1.Extend(10, 0)
+
+		TestCases{
+			{"cost", re.Cost(), 5.0},
+			{"estimate", m.RailCost(&Point{0, 0}, &Point{10, 0}), re.Cost()},
+		}.Assert(t)
+	})
 	t.Run("CheckDelete", func(t *testing.T) {
 		t.Run("relay", func(t *testing.T) {
 			m := NewModel(c, a)
@@ -9,10 +9,11 @@ type RailLine struct {
 	Base
 	Persistence
 
-	Name      string `json:"name"`
-	AutoExt   bool   `json:"auto_ext"`
-	AutoPass  bool   `json:"auto_pass"`
-	ReRouting bool   `gorm:"-" json:"-"`
+	Name     string `json:"name"`
+	AutoExt  bool   `json:"auto_ext"`
+	AutoPass bool   `json:"auto_pass"`
+	// ReRouting is true while Transports of RailLine are waiting for refresh.
+	ReRouting bool `gorm:"-" json:"rerouting"`
 
 	RailEdges map[uint]*RailEdge `gorm:"-" json:"-"`
 	Stops     map[uint]*Platform `gorm:"-" json:"-"`
@@ -122,7 +123,9 @@ func (l *RailLine) RingIf() bool {
 // ClearTransports eraces Transport information.
 func (l *RailLine) ClearTransports() {
 	for _, p := range l.Stops {
-		p.Transports = make(map[uint]*Transport)
+		for _, x := range p.Transports {
+			l.M.Delete(x)
+		}
 	}
 }
 
@@ -200,6 +203,7 @@ func (l *RailLine) Delete() {
 	for _, t := range l.Trains {
 		t.SetTask(nil)
 	}
+	l.ClearTransports()
 	for _, lt := range l.Tasks {
 		lt.Delete()
 	}
@@ -15,9 +15,12 @@ type Residence struct {
 	// Wait represents how msec after it generates Human
 	Wait float64 `json:"wait"`
 	Name string  `json:"name"`
+	// Demand is multiplier of Human generation
+	Demand float64 `gorm:"not null;default:1" json:"demand"`
 
 	Targets map[uint]*Human `gorm:"-" json:"-"`
 	out     map[uint]*Step
+	in      map[uint]*Step
 }
 
 // NewResidence create new instance without setting parameters
@@ -28,6 +31,7 @@ func (m *Model) NewResidence(x float64, y float64) *Residence {
 		Point:       NewPoint(x, y),
 		Capacity:    m.conf.Residence.Capacity,
 		Wait:        m.conf.Residence.Interval.D.Seconds() * rand.Float64(),
+		Demand:      initialDemand(m.conf.Residence.Demand),
 	}
 	r.Init(m)
 	r.Resolve()
@@ -35,6 +39,7 @@ func (m *Model) NewResidence(x float64, y float64) *Residence {
 	m.Add(r)
 
 	r.GenOutSteps()
+	r.GenInSteps()
 	return r
 }
 
@@ -53,6 +58,60 @@ func (r *Residence) Pos() *Point {
 	return &r.Point
 }
 
+// initialDemand returns configured multiplier of demand. 0 means 1.
+func initialDemand(v float64) float64 {
+	if v == 0 {
+		return 1
+	}
+	return v
+}
+
+// Step procceed it with specified time.
+// Waiting time passes faster as Demand is bigger.
+// When waiting time passes, it generates Human up to Capacity and returns them.
+// Human living in it counts toward Capacity until it returns or dies.
+func (r *Residence) Step(sec float64) []*Human {
+	r.Wait -= sec * r.Demand
+	if r.Wait > 0 {
+		return nil
+	}
+	r.Wait += r.M.conf.Residence.Interval.D.Seconds()
+	hs := []*Human{}
+	for i := len(r.Targets); i < r.Capacity; i++ {
+		c := r.chooseDestination()
+		if c == nil {
+			break
+		}
+		hs = append(hs, r.M.NewHuman(r, c))
+	}
+	return hs
+}
+
+// chooseDestination returns Company randomly weighted by its Attract and Demand.
+// Companies are scanned in order of id so that the same random number chooses the same Company.
+func (r *Residence) chooseDestination() *Company {
+	var sum float64
+	ids := r.M.SortedIDs(COMPANY)
+	for _, id := range ids {
+		sum += r.M.Companies[id].Weight()
+	}
+	if sum <= 0 {
+		return nil
+	}
+	v := rand.Float64() * sum
+	var last *Company
+	for _, id := range ids {
+		c := r.M.Companies[id]
+		if v < c.Weight() {
+			return c
+		}
+		v -= c.Weight()
+		last = c
+	}
+	// floating point error
+	return last
+}
+
 // GenOutSteps generates Steps from this Residence.
 func (r *Residence) GenOutSteps() {
 	// R -> C
@@ -65,10 +124,23 @@ func (r *Residence) GenOutSteps() {
 	}
 }
 
+// GenInSteps generates Steps to this Residence for going home.
+func (r *Residence) GenInSteps() {
+	// C -> R
+	for _, c := range r.M.Companies {
+		r.M.NewStep(c, r)
+	}
+	// G -> R
+	for _, g := range r.M.Gates {
+		r.M.NewStep(g, r)
+	}
+}
+
 // Init creates map.
 func (r *Residence) Init(m *Model) {
 	r.Base.Init(RESIDENCE, m)
 	r.out = make(map[uint]*Step)
+	r.in = make(map[uint]*Step)
 	r.Targets = make(map[uint]*Human)
 }
 
@@ -79,7 +151,7 @@ func (r *Residence) OutSteps() map[uint]*Step {
 
 // InSteps returns where it comes from
 func (r *Residence) InSteps() map[uint]*Step {
-	return nil
+	return r.in
 }
 
 // Resolve set reference
@@ -117,16 +189,19 @@ func (r *Residence) CheckDelete() error {
 // Delete removes this entity with related ones.
 func (r *Residence) Delete() {
 	for _, h := range r.Targets {
-		r.M.Delete(h)
+		h.Delete()
 	}
 	for _, s := range r.out {
 		r.M.Delete(s)
 	}
+	for _, s := range r.in {
+		r.M.Delete(s)
+	}
 	r.M.Delete(r)
 }
 
 func (r *Residence) String() string {
 	r.Marshal()
-	return fmt.Sprintf("%s(%d):i=0,o=%d,h=%d:%v:%s", r.Type().Short(),
-		r.ID, len(r.out), len(r.Targets), r.Pos(), r.Name)
+	return fmt.Sprintf("%s(%d):i=%d,o=%d,h=%d:%v:%s", r.Type().Short(),
+		r.ID, len(r.in), len(r.out), len(r.Targets), r.Pos(), r.Name)
 }
@@ -0,0 +1,148 @@
+package entities
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/yasshi2525/RushHour/auth"
+	"github.com/yasshi2525/RushHour/config"
+)
+
+func TestResidence(t *testing.T) {
+	a, _ := auth.GetAuther(config.CnfAuth{Key: "----------------"})
+	c := config.CnfEntity{
+		MaxScale:  16,
+		Residence: config.CnfResidence{Capacity: 2},
+		Company:   config.CnfCompany{Attract: 1},
+		Human:     config.CnfHuman{Speed: 1},
+	}
+	c.Residence.Interval.D = 10 * time.Second
+
+	t.Run("Step", func(t *testing.T) {
+		t.Run("wait", func(t *testing.T) {
+			m := NewModel(c, a)
+			m.NewCompany(10, 10)
+			r := m.NewResidence(0, 0)
+			r.Wait = 5
+
+			TestCases{
+				{"h", len(r.Step(1)), 0},
+				{"wait", r.Wait, 4.0},
+				{"model", len(m.Humans), 0},
+			}.Assert(t)
+		})
+		t.Run("generate", func(t *testing.T) {
+			m := NewModel(c, a)
+			cp := m.NewCompany(10, 10)
+			r := m.NewResidence(0, 0)
+			r.Wait = 1
+
+			hs := r.Step(2)
+
+			TestCases{
+				{"h", len(hs), 2},
+				{"wait", r.Wait, 9.0},
+				{"model", len(m.Humans), 2},
+				{"r", hs[0].From, r},
+				{"c", hs[0].To, cp},
+				{"targets", len(r.Targets), 2},
+			}.Assert(t)
+		})
+		t.Run("capacity", func(t *testing.T) {
+			m := NewModel(c, a)
+			m.NewCompany(10, 10)
+			r := m.NewResidence(0, 0)
+			r.Wait = 1
+
+			for i := 0; i < 5; i++ {
+				r.Step(10)
+			}
+
+			TestCases{
+				{"targets", len(r.Targets), 2},
+				{"model", len(m.Humans), 2},
+			}.Assert(t)
+
+			for _, h := range r.Targets {
+				h.Delete()
+				break
+			}
+
+			TestCases{
+				{"h", len(r.Step(10)), 1},
+				{"targets", len(r.Targets), 2},
+			}.Assert(t)
+		})
+		t.Run("no company", func(t *testing.T) {
+			m := NewModel(c, a)
+			r := m.NewResidence(0, 0)
+			r.Wait = 1
+
+			TestCases{
+				{"h", len(r.Step(2)), 0},
+			}.Assert(t)
+		})
+		t.Run("attract", func(t *testing.T) {
+			m := NewModel(c, a)
+			m.NewCompany(10, 10).Attract = 0
+			cp := m.NewCompany(20, 20)
+			r := m.NewResidence(0, 0)
+			r.Capacity = 10
+			r.Wait = 1
+
+			for _, h := range r.Step(2) {
+				if h.To != cp {
+					t.Errorf("h.To wants %v, but %v", cp, h.To)
+				}
+			}
+		})
+		t.Run("demand", func(t *testing.T) {
+			m := NewModel(c, a)
+			m.NewCompany(10, 10).Demand = 0
+			cp := m.NewCompany(20, 20)
+			r := m.NewResidence(0, 0)
+			r.Wait = 5
+			r.Demand = 2
+
+			hs := r.Step(3)
+
+			TestCases{
+				{"h", len(hs), 2},
+				{"wait", r.Wait, 9.0},
+				{"c", hs[0].To, cp},
+			}.Assert(t)
+		})
+		t.Run("configured demand", func(t *testing.T) {
+			c := c
+			c.Residence.Demand = 3
+			c.Company.Demand = 0.5
+			m := NewModel(c, a)
+
+			TestCases{
+				{"r", m.NewResidence(0, 0).Demand, 3.0},
+				{"c", m.NewCompany(10, 10).Demand, 0.5},
+			}.Assert(t)
+		})
+		t.Run("reproducible", func(t *testing.T) {
+			m := NewModel(c, a)
+			for i := 0; i < 10; i++ {
+				m.NewCompany(float64(i), 0)
+			}
+			r := m.NewResidence(0, 0)
+			choose := func() string {
+				rand.Seed(1)
+				ids := []uint{}
+				for i := 0; i < 10; i++ {
+					ids = append(ids, r.chooseDestination().ID)
+				}
+				return fmt.Sprint(ids)
+			}
+
+			TestCases{
+				{"ids", choose(), choose()},
+			}.Assert(t)
+		})
+	})
+}
@@ -111,10 +111,10 @@ func (st *Station) BeforeDelete() {
 // Delete removes this entity with related ones.
 func (st *Station) Delete() {
 	if st.Gate != nil {
-		st.M.Delete(st.Gate)
+		st.Gate.Delete()
 	}
 	if st.Platform != nil {
-		st.M.Delete(st.Platform)
+		st.Platform.Delete()
 	}
 	st.M.Delete(st)
 }
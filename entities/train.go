@@ -2,6 +2,7 @@ package entities
 
 import (
 	"fmt"
+	"sort"
 )
 
 // EPS represents ignore difference when it compares two float value
@@ -58,13 +59,20 @@ func (t *Train) P() *Persistence {
 	return &t.Persistence
 }
 
+// Pos returns location of departure of current LineTask.
+// It is used for deploying Train over Chunk, so it doesn't change while running on the same LineTask.
+// It returns nil when Train has no LineTask.
+func (t *Train) Pos() *Point {
+	if t.task == nil {
+		return nil
+	}
+	return t.task.FromNode().Pos()
+}
+
 // UnLoad unregisters all Human ride on it forcefully.
 func (t *Train) UnLoad() {
 	for _, h := range t.Passengers {
-		h.Point = *t.Point.Rand(t.M.conf.Train.Randomize)
-		h.onTrain = nil
-		h.TrainID = ZERO
-		t.Occupied--
+		h.GetOffForce()
 	}
 }
 
@@ -76,8 +84,13 @@ func (t *Train) Step(sec float64) {
 	for sec > EPS {
 		switch t.task.TaskType {
 		case OnDeparture:
-			// [TODO] make human get off
-			t.SetTask(t.task.next)
+			// Progress accumulates time for Human getting off and on
+			t.Progress += sec
+			sec = 0
+			if t.load() {
+				sec = t.Progress
+				t.SetTask(t.task.next)
+			}
 		default:
 			t.task.Step(&t.Progress, &sec)
 			if t.Progress > 1-EPS {
@@ -87,6 +100,53 @@ func (t *Train) Step(sec float64) {
 		//log.Printf("t(%d) sec = %f prod = %f: %v", t.ID, sec, t.Progress, t)
 	}
 	t.X, t.Y = t.task.Loc(t.Progress).Flat()
+	for _, h := range t.Passengers {
+		h.Point = t.Point
+	}
+	t.M.RootCluster.Touch(t)
+}
+
+// load makes passengers get off and waiting Human get in at Platform.
+// One Human takes 1/Mobility seconds to get off or get in.
+// Passengers are handled in order of id to be reproducible.
+// It returns false when there is no time to finish it.
+func (t *Train) load() bool {
+	p := t.task.Stay
+	interval := 1.0 / float64(t.Mobility)
+	alighted := make(map[uint]bool)
+	for _, h := range sortHumans(t.Passengers, func(h *Human) bool { return h.ShouldGetOff(t) }) {
+		if t.Progress < interval {
+			return false
+		}
+		h.GetOff(p)
+		alighted[h.ID] = true
+		t.Progress -= interval
+	}
+	for _, h := range sortHumans(p.Passengers, func(h *Human) bool { return !alighted[h.ID] && h.ShouldGetIn(t) }) {
+		// Train may become full by preceding ones
+		if !h.ShouldGetIn(t) {
+			continue
+		}
+		if t.Progress < interval {
+			return false
+		}
+		h.GetIn(t)
+		t.Progress -= interval
+	}
+	return true
+}
+
+// sortHumans returns Human satisfying cond in order of id.
+// Only the filtered ones are sorted because most passengers don't move at each Platform.
+func sortHumans(hs map[uint]*Human, cond func(*Human) bool) []*Human {
+	list := []*Human{}
+	for _, h := range hs {
+		if cond(h) {
+			list = append(list, h)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
 }
 
 // Idx returns unique id field.
@@ -106,7 +166,14 @@ func (t *Train) Init(m *Model) {
 }
 
 // SetTask change current LineTask to specified one.
+// Train is redeployed over Chunk because its position depends on LineTask.
 func (t *Train) SetTask(lt *LineTask) {
+	t.M.RootCluster.Remove(t)
+	t.setTask(lt)
+	t.M.RootCluster.Add(t)
+}
+
+func (t *Train) setTask(lt *LineTask) {
 	if t.task != nil {
 		if lt == nil {
 			t.task.RailLine.UnResolve(t)
@@ -188,6 +255,11 @@ func (t *Train) UnResolve(args ...interface{}) {
 		switch obj := raw.(type) {
 		case *Platform:
 			delete(obj.Trains, t.ID)
+		case *Human:
+			if _, ok := t.Passengers[obj.ID]; ok {
+				delete(t.Passengers, obj.ID)
+				t.Occupied--
+			}
 		default:
 			panic(fmt.Errorf("invalid type: %T %+v", obj, obj))
 		}
@@ -156,8 +156,8 @@ func InitType() {
 		n := reflect.TypeOf((*Relayable)(nil)).Elem()
 		e := reflect.TypeOf((*Connectable)(nil)).Elem()
 
-		nodes[res] = types[res].Implements(n)
-		edges[res] = types[res].Implements(e)
+		nodes[res] = reflect.PtrTo(types[res]).Implements(n)
+		edges[res] = reflect.PtrTo(types[res]).Implements(e)
 	}
 
 	delegateTypes = make(map[ModelType]reflect.Type)
@@ -165,7 +165,11 @@ func InitType() {
 	delegateTypes[COMPANY] = reflect.TypeOf(DelegateCompany{})
 	delegateTypes[RAILNODE] = reflect.TypeOf(DelegateRailNode{})
 	delegateTypes[RAILEDGE] = reflect.TypeOf(DelegateRailEdge{})
+	delegateTypes[PLATFORM] = reflect.TypeOf(DelegatePlatform{})
+	delegateTypes[TRAIN] = reflect.TypeOf(DelegateTrain{})
+	delegateTypes[LINETASK] = reflect.TypeOf(DelegateLineTask{})
 
 	connectTypes = make(map[ModelType]ModelType)
 	connectTypes[RAILEDGE] = RAILNODE
+	connectTypes[LINETASK] = RAILNODE
 }
@@ -19,7 +19,6 @@ import (
 	"github.com/gin-contrib/sessions/cookie"
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
-	_ "github.com/go-sql-driver/mysql"
 
 	lumberjack "gopkg.in/natefinch/lumberjack.v2"
 
@@ -120,11 +119,22 @@ func setupRouter(secret string) *gin.Engine {
 			// no need auth (only under operation)
 			shared := ops.Group("/", v1.ModelHandler())
 			{
-				shared.GET("/gamemap", v1.GameMap)
-				shared.GET("/players", v1.Players)
 				shared.POST("/register", v1.Register)
 			}
 
+			// published view and route are read without lock of model (only under operation)
+			view := ops.Group("/", v1.ViewHandler())
+			{
+				view.GET("/gamemap", v1.GameMap)
+				view.GET("/ranking", v1.Ranking)
+				view.GET("/journey", v1.Journey)
+			}
+
+			// long-lived connection must not hold lock of model (only under operation)
+			ops.GET("/gamemap/stream", v1.Stream)
+			// published view is read without lock of model (only under operation)
+			ops.GET("/players", v1.Players)
+
 			// need user authorization (only under operation)
 			user := ops.Group("/", v1.JWTHandler(), v1.ModelHandler())
 			{
@@ -135,6 +145,17 @@ func setupRouter(secret string) *gin.Engine {
 				user.POST("/rail_nodes/extend", v1.Extend)
 				user.POST("/rail_nodes/connect", v1.Connect)
 				user.DELETE("/rail_nodes", v1.RemoveRailNode)
+				user.POST("/stations", v1.CreateStation)
+				user.DELETE("/stations", v1.RemoveStation)
+				user.POST("/rail_lines", v1.CreateRailLine)
+				user.POST("/rail_lines/start", v1.StartRailLine)
+				user.POST("/rail_lines/insert", v1.InsertRailLine)
+				user.POST("/rail_lines/ring", v1.RingRailLine)
+				user.DELETE("/rail_lines", v1.RemoveRailLine)
+				user.POST("/trains", v1.CreateTrain)
+				user.POST("/trains/deploy", v1.DeployTrain)
+				user.POST("/trains/undeploy", v1.UnDeployTrain)
+				user.DELETE("/trains", v1.RemoveTrain)
 			}
 		}
 
@@ -145,15 +166,27 @@ func setupRouter(secret string) *gin.Engine {
 			shared := always.Group("/", v1.ModelHandler())
 			{
 				shared.POST("/login", v1.Login) // forbit normal user under maintenance
-				shared.GET("/game", v1.GameStatus)
 				shared.GET("/game/const", v1.GameConst)
 			}
+			// published view is read without lock of model (always)
+			always.GET("/game", v1.GameStatus)
 			// need administrator authorization (always)
 			admin := always.Group("/", v1.JWTHandler(), v1.AdminHandler(), v1.ModelHandler())
 			{
 				admin.POST("/game/start", v1.StartGame)
 				admin.POST("/game/stop", v1.StopGame)
+				admin.POST("/game/speed", v1.ChangeSpeed)
+				admin.POST("/residences/demand", v1.ChangeResidenceDemand)
+				admin.POST("/companies/demand", v1.ChangeCompanyDemand)
 				admin.DELETE("/game/purge", v1.PurgeUserData)
+				admin.GET("/route", v1.DumpRoute)
+				admin.POST("/snapshot", v1.UploadSnapshot)
+				admin.GET("/backup", v1.BackupStats)
+			}
+			// need administrator authorization and lock model by itself (always)
+			raw := always.Group("/", v1.JWTHandler(), v1.AdminHandler())
+			{
+				raw.GET("/snapshot", v1.DownloadSnapshot)
 			}
 		}
 	}
@@ -176,6 +209,19 @@ func main() {
 	} else if auther, err := auth.GetAuther(conf.Secret.Auth); err != nil {
 		panic(err)
 	} else {
+		// maintenance of database schema
+		if len(os.Args) > 1 && os.Args[1] == "migrate" {
+			if err := runMigrate(conf, auther, os.Args[2:], os.Stdout); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+		// fixed seed reproduces random choices of simulation stepped in the same way.
+		// Background routing is applied at tick boundary, but which tick depends on how long it takes,
+		// so live games diverge once routing ends at different tick.
+		if seed := conf.Game.Service.Clock.Seed; seed != 0 {
+			rand.Seed(seed)
+		}
 		readiness = "initializing ..."
 
 		router := setupRouter(conf.Secret.Auth.Cookie)
@@ -210,7 +256,9 @@ func main() {
 
 		log.Println("Shutdown Server ...")
 
-		services.Stop()
+		if err := services.Stop(); err != nil {
+			log.Printf("failed to stop game: %v", err)
+		}
 		services.Terminate()
 		readiness = "shut down ..."
 
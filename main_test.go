@@ -1,7 +1,12 @@
 package main
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
+
+	"github.com/yasshi2525/RushHour/auth"
 )
 
 func TestSetupRouter(t *testing.T) {
@@ -9,3 +14,42 @@ func TestSetupRouter(t *testing.T) {
 		t.Errorf("setupRouter(%s) got nil, want not nil", "test")
 	}
 }
+
+func TestRunMigrate(t *testing.T) {
+	conf, err := loadConf()
+	if err != nil {
+		t.Fatal(err)
+	}
+	auther, _ := auth.GetAuther(conf.Secret.Auth)
+	dir, err := ioutil.TempDir("", "rushhour")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	conf.Secret.DB.Driver = "sqlite3"
+	conf.Secret.DB.Spec = filepath.Join(dir, "rushhour.db")
+
+	cases := []struct {
+		args []string
+		ok   bool
+	}{
+		{[]string{"check"}, false},
+		{[]string{"up"}, true},
+		{[]string{"check"}, true},
+		{[]string{"up", "-to", "0"}, false},
+		{[]string{"down"}, true},
+		{[]string{"check"}, false},
+		{[]string{"down", "-to", "1"}, true},
+		{[]string{"down"}, false},
+		{[]string{"down", "-force"}, true},
+		{[]string{"down"}, false},
+		{[]string{"unknown"}, false},
+		{[]string{}, false},
+	}
+	for _, c := range cases {
+		err := runMigrate(conf, auther, c.args, ioutil.Discard)
+		if got := err == nil; got != c.ok {
+			t.Errorf("runMigrate(%v) got %v, want ok=%v", c.args, err, c.ok)
+		}
+	}
+}
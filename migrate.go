@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/yasshi2525/RushHour/auth"
+	"github.com/yasshi2525/RushHour/config"
+	"github.com/yasshi2525/RushHour/services"
+)
+
+const migrateUsage = `usage: RushHour migrate <command> [-to version] [-force]
+
+commands:
+  up     apply steps until latest or specified version
+  down   revert last step or steps until specified version.
+         reverting to version 0 drops all tables, so it requires -force
+  check  fail when database is not latest version or lacks columns
+`
+
+// runMigrate maintains schema of database without starting game.
+func runMigrate(conf *config.Config, auther *auth.Auther, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+	cmd := args[0]
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	fs.SetOutput(out)
+	to := fs.Int("to", -1, "target version")
+	force := fs.Bool("force", false, "allow down to drop all tables")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	return services.Maintain(conf, auther, func() error {
+		current, err := services.CurrentSchema()
+		if err != nil {
+			return err
+		}
+		latest := services.LatestSchema()
+		switch cmd {
+		case "up":
+			target := latest
+			if *to >= 0 {
+				target = uint(*to)
+			}
+			if target < current {
+				return fmt.Errorf("target version %d is older than current %d. use down", target, current)
+			}
+			return services.MigrateSchema(target)
+		case "down":
+			if current == 0 {
+				return fmt.Errorf("no step is applied")
+			}
+			target := current - 1
+			if *to >= 0 {
+				target = uint(*to)
+			}
+			if target > current {
+				return fmt.Errorf("target version %d is newer than current %d. use up", target, current)
+			}
+			if target == 0 && !*force {
+				return fmt.Errorf("down to version 0 drops all tables. use -force")
+			}
+			return services.MigrateSchema(target)
+		case "check":
+			fmt.Fprintf(out, "current %d, latest %d\n", current, latest)
+			if current != latest {
+				return fmt.Errorf("schema version %d differs from latest %d", current, latest)
+			}
+			return services.VerifySchema()
+		default:
+			return fmt.Errorf("unknown command %s\n%s", cmd, migrateUsage)
+		}
+	})
+}
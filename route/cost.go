@@ -0,0 +1,71 @@
+package route
+
+import (
+	"math"
+
+	"github.com/yasshi2525/RushHour/entities"
+)
+
+// CostModel calculates cost of Edge for Human considering congestion.
+// Nil CostModel means cost of Connectable as it is.
+type CostModel struct {
+	// Crowd is penalty in seconds for entering Platform full of waiting Human.
+	Crowd float64
+	// Full is penalty in seconds for riding on RailLine whose Trains are full.
+	Full float64
+	// Wait is ratio of expected waiting time for Train to headway of RailLine.
+	Wait float64
+}
+
+// Cost returns seconds Human takes to go through specified Step or Transport.
+// Penalty increases in proportion to occupancy of Platform or Trains.
+func (c *CostModel) Cost(obj entities.Connectable) float64 {
+	cost := obj.Cost()
+	if c == nil {
+		return cost
+	}
+	switch x := obj.(type) {
+	case *entities.Step:
+		if p, ok := x.ToNode.(*entities.Platform); ok {
+			cost += c.Crowd * ratio(p.Occupied, p.Capacity)
+		}
+	case *entities.Transport:
+		if x.Via != nil && x.Via.RailLine != nil {
+			l := x.Via.RailLine
+			cost += c.Wait*headway(l) + c.Full*load(l)
+		}
+	}
+	return cost
+}
+
+// headway returns interval of Trains running on RailLine in seconds.
+// Cost of departure LineTask represents it.
+func headway(l *entities.RailLine) float64 {
+	for _, lt := range l.Tasks {
+		if lt.TaskType == entities.OnDeparture {
+			if v := lt.Cost(); v < math.MaxFloat64 {
+				return v
+			}
+			return 0
+		}
+	}
+	return 0
+}
+
+// load returns occupancy of Trains running on RailLine.
+func load(l *entities.RailLine) float64 {
+	var occupied, capacity int
+	for _, t := range l.Trains {
+		occupied += t.Occupied
+		capacity += t.Capacity
+	}
+	return ratio(occupied, capacity)
+}
+
+// ratio returns occupancy in [0, 1]. Zero capacity is regarded as full.
+func ratio(occupied int, capacity int) float64 {
+	if capacity <= 0 {
+		return 1
+	}
+	return math.Min(float64(occupied)/float64(capacity), 1)
+}
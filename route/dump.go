@@ -0,0 +1,100 @@
+package route
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/yasshi2525/RushHour/entities"
+)
+
+// Ref directs resource of model in readable form.
+type Ref struct {
+	Type string `json:"type"`
+	ID   uint   `json:"id"`
+}
+
+func newRef(d Digest) Ref {
+	return Ref{d.ModelType.String(), d.ID}
+}
+
+func (r Ref) String() string {
+	return fmt.Sprintf("%s(%d)", r.Type, r.ID)
+}
+
+// DumpNode is Node of shortest path tree.
+type DumpNode struct {
+	Ref
+	// Value is distance to goal
+	Value float64 `json:"value"`
+	// Edge is Step or Transport to go through next
+	Edge Ref `json:"edge"`
+	// Next is Node to go next
+	Next Ref `json:"next"`
+}
+
+// Dump is shortest path tree towards goal for diagnosis.
+type Dump struct {
+	Goal Ref `json:"goal"`
+	// Nodes is Nodes which reach goal
+	Nodes []*DumpNode `json:"nodes"`
+	// Unreachable is Nodes which don't reach goal
+	Unreachable []Ref `json:"unreachable"`
+}
+
+// NewDump exports route of model searched for specified goal.
+// Nodes are sorted by type and id.
+func NewDump(model *Model, t entities.ModelType, id uint) *Dump {
+	d := &Dump{Goal: Ref{t.String(), id}, Nodes: []*DumpNode{}, Unreachable: []Ref{}}
+	for _, ns := range model.Nodes {
+		for _, n := range ns {
+			if n.ModelType == t && n.ID == id {
+				continue
+			}
+			if n.ViaEdge == nil {
+				d.Unreachable = append(d.Unreachable, newRef(n.Digest))
+				continue
+			}
+			d.Nodes = append(d.Nodes, &DumpNode{
+				Ref:   newRef(n.Digest),
+				Value: n.Value,
+				Edge:  newRef(n.ViaEdge.Digest),
+				Next:  newRef(n.ViaEdge.ToNode.Digest),
+			})
+		}
+	}
+	sort.Slice(d.Nodes, func(i, j int) bool {
+		return less(d.Nodes[i].Ref, d.Nodes[j].Ref)
+	})
+	sort.Slice(d.Unreachable, func(i, j int) bool {
+		return less(d.Unreachable[i], d.Unreachable[j])
+	})
+	return d
+}
+
+// DOT returns shortest path tree in Graphviz DOT language.
+// Goal is drawn as double circle and unreachable Nodes are drawn with dashed line.
+func (d *Dump) DOT() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph \"%v\" {\n", d.Goal)
+	fmt.Fprintf(&b, "  \"%v\" [shape=doublecircle];\n", d.Goal)
+	for _, n := range d.Nodes {
+		fmt.Fprintf(&b, "  \"%v\" [label=\"%v\\n%.2f\"];\n", n.Ref, n.Ref, n.Value)
+	}
+	for _, r := range d.Unreachable {
+		fmt.Fprintf(&b, "  \"%v\" [style=dashed];\n", r)
+	}
+	for _, n := range d.Nodes {
+		fmt.Fprintf(&b, "  \"%v\" -> \"%v\" [label=\"%v\"];\n", n.Ref, n.Next, n.Edge)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// less orders Ref by type and id.
+func less(a Ref, b Ref) bool {
+	if a.Type != b.Type {
+		return a.Type < b.Type
+	}
+	return a.ID < b.ID
+}
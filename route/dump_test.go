@@ -0,0 +1,47 @@
+package route
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yasshi2525/RushHour/entities"
+)
+
+func TestDump(t *testing.T) {
+	entities.InitType()
+	// 3(isolated)  2 -> 1(goal)
+	graph := genGrid(2, 1, 1)
+	graph.RemoveEdge(entities.STEP, 1)
+	graph.Nodes[entities.GATE][3] = &Node{Digest: Digest{entities.GATE, 3, 0}, index: -1}
+	model, goal := graph.ExportWith(entities.GATE, 1)
+	goal.WalkThrough()
+	model.Fix()
+
+	d := NewDump(model, entities.GATE, 1)
+	if got := len(d.Nodes); got != 1 {
+		t.Fatalf("len(Nodes) got %d, want 1", got)
+	}
+	if got, want := d.Nodes[0].Ref, (Ref{entities.GATE.String(), 2}); got != want {
+		t.Errorf("Nodes[0] got %v, want %v", got, want)
+	}
+	if got, want := d.Nodes[0].Next, d.Goal; got != want {
+		t.Errorf("Nodes[0].Next got %v, want %v", got, want)
+	}
+	if got, want := d.Nodes[0].Edge, (Ref{entities.STEP.String(), 2}); got != want {
+		t.Errorf("Nodes[0].Edge got %v, want %v", got, want)
+	}
+	if got := len(d.Unreachable); got != 1 || d.Unreachable[0].ID != 3 {
+		t.Errorf("Unreachable got %v, want [%s(3)]", d.Unreachable, entities.GATE)
+	}
+
+	dot := d.DOT()
+	for _, want := range []string{
+		"digraph",
+		d.Nodes[0].Ref.String() + "\" -> \"" + d.Goal.String(),
+		d.Unreachable[0].String() + "\" [style=dashed]",
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("DOT() got %s, want containing %s", dot, want)
+		}
+	}
+}
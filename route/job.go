@@ -0,0 +1,38 @@
+package route
+
+import (
+	"context"
+
+	"github.com/yasshi2525/RushHour/entities"
+)
+
+// Job refreshes tracks of Player or transports of RailLine.
+// Creating Job scans model and Apply reflects the result to model, so both require lock of model.
+// Search refers to copy of model only, so it can run without lock.
+type Job struct {
+	template *Model
+	payload  *Payload
+	apply    func(map[uint]*Model)
+}
+
+// Search calculates route between RailNodes with Interactive priority.
+// It returns false when search is canceled.
+func (j *Job) Search(ctx context.Context) bool {
+	if j.template == nil {
+		return true
+	}
+	var ok bool
+	j.payload, ok = Search(ctx, entities.RAILNODE, Interactive, j.template)
+	return ok
+}
+
+// Apply reflects searched route to model.
+// It returns minimum distance route for each goal.
+func (j *Job) Apply() map[uint]*Model {
+	var route map[uint]*Model
+	if j.payload != nil {
+		route = j.payload.Route
+	}
+	j.apply(route)
+	return route
+}
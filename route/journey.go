@@ -0,0 +1,190 @@
+package route
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// Path is sequence of Edges from origin to goal.
+type Path struct {
+	Edges []*Edge
+	// Value is sum of cost of Edges
+	Value float64
+}
+
+// PathTo returns Path of graph from origin along route of model searched for goal.
+// It returns nil when origin doesn't reach goal.
+func PathTo(graph *Model, model *Model, origin *Node) *Path {
+	if model == nil || origin == nil {
+		return nil
+	}
+	n, ok := model.Nodes[origin.ModelType][origin.ID]
+	if !ok {
+		return nil
+	}
+	p := &Path{Edges: []*Edge{}}
+	for x := n; x.ViaEdge != nil; x = x.ViaEdge.ToNode {
+		e, ok := graph.Edges[x.ViaEdge.ModelType][x.ViaEdge.ID]
+		if !ok || len(p.Edges) > model.NumNodes() {
+			return nil
+		}
+		p.Edges = append(p.Edges, e)
+		p.Value += e.Cost()
+	}
+	if len(p.Edges) == 0 {
+		return nil
+	}
+	return p
+}
+
+// Paths returns at most k shortest loopless Paths from origin to goal in ascending order of Value (Yen's algorithm).
+// The first Path is searched unless it is specified.
+func Paths(origin *Node, goal *Node, k int, first *Path) []*Path {
+	if first == nil {
+		first = shortestPath(origin, goal, nil, nil)
+	}
+	if first == nil || k <= 0 {
+		return []*Path{}
+	}
+	result := []*Path{first}
+	candidates := []*Path{}
+	for len(result) < k {
+		last := result[len(result)-1]
+		for i := range last.Edges {
+			spur := origin
+			if i > 0 {
+				spur = last.Edges[i-1].ToNode
+			}
+			root := last.Edges[:i]
+
+			bannedEdges := make(map[*Edge]bool)
+			for _, p := range result {
+				if len(p.Edges) > i && hasPrefix(p, root) {
+					bannedEdges[p.Edges[i]] = true
+				}
+			}
+			bannedNodes := map[*Node]bool{}
+			for _, e := range root {
+				bannedNodes[e.FromNode] = true
+			}
+
+			sp := shortestPath(spur, goal, bannedNodes, bannedEdges)
+			if sp == nil {
+				continue
+			}
+			total := &Path{Edges: append(append([]*Edge{}, root...), sp.Edges...)}
+			for _, e := range total.Edges {
+				total.Value += e.Cost()
+			}
+			if !containsPath(result, total) && !containsPath(candidates, total) {
+				candidates = append(candidates, total)
+			}
+		}
+		if len(candidates) == 0 {
+			break
+		}
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return candidates[i].Value < candidates[j].Value
+		})
+		result = append(result, candidates[0])
+		candidates = candidates[1:]
+	}
+	return result
+}
+
+// shortestPath searches minimum Path from origin to goal avoiding banned Nodes and Edges.
+// Value of Nodes is not changed in order to share graph.
+func shortestPath(origin *Node, goal *Node, bannedNodes map[*Node]bool, bannedEdges map[*Edge]bool) *Path {
+	dist := map[*Node]float64{origin: 0}
+	prev := make(map[*Node]*Edge)
+	done := make(map[*Node]bool)
+	q := &pathQueue{{origin, 0}}
+
+	for q.Len() > 0 {
+		x := heap.Pop(q).(pathItem)
+		if done[x.node] {
+			continue
+		}
+		done[x.node] = true
+		if x.node == goal {
+			break
+		}
+		for _, e := range x.node.Out {
+			y := e.ToNode
+			if done[y] || bannedNodes[y] || bannedEdges[e] {
+				continue
+			}
+			v := x.value + e.Cost()
+			if d, ok := dist[y]; !ok || v < d {
+				dist[y], prev[y] = v, e
+				heap.Push(q, pathItem{y, v})
+			}
+		}
+	}
+	if !done[goal] || origin == goal {
+		return nil
+	}
+	edges := []*Edge{}
+	for x := goal; x != origin; x = prev[x].FromNode {
+		edges = append([]*Edge{prev[x]}, edges...)
+	}
+	return &Path{Edges: edges, Value: dist[goal]}
+}
+
+// hasPrefix returns whether Path starts with specified Edges or not.
+func hasPrefix(p *Path, prefix []*Edge) bool {
+	if len(p.Edges) < len(prefix) {
+		return false
+	}
+	for i, e := range prefix {
+		if p.Edges[i] != e {
+			return false
+		}
+	}
+	return true
+}
+
+// containsPath returns whether list has same Path or not.
+func containsPath(list []*Path, p *Path) bool {
+	for _, x := range list {
+		if len(x.Edges) == len(p.Edges) && hasPrefix(x, p.Edges) {
+			return true
+		}
+	}
+	return false
+}
+
+// pathItem is Node with tentative distance from origin.
+type pathItem struct {
+	node  *Node
+	value float64
+}
+
+// pathQueue is binary heap of pathItem ordered by value.
+// It keeps Value of Node as it is, unlike NodeQueue.
+type pathQueue []pathItem
+
+func (q pathQueue) Len() int {
+	return len(q)
+}
+
+func (q pathQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q pathQueue) Less(i, j int) bool {
+	return q[i].value < q[j].value
+}
+
+// Push appends item. Use heap.Push instead of calling it directly.
+func (q *pathQueue) Push(x interface{}) {
+	*q = append(*q, x.(pathItem))
+}
+
+// Pop removes last item. Use heap.Pop instead of calling it directly.
+func (q *pathQueue) Pop() interface{} {
+	old := *q
+	x := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return x
+}
@@ -0,0 +1,72 @@
+package route
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/yasshi2525/RushHour/entities"
+)
+
+func TestPaths(t *testing.T) {
+	for _, seed := range []int64{1, 2, 3} {
+		t.Run(fmt.Sprintf("seed%d", seed), func(t *testing.T) {
+			graph := genGrid(4, 4, seed)
+			origin, goal := graph.Nodes[entities.GATE][16], graph.Nodes[entities.GATE][1]
+
+			model, g := graph.ExportWith(entities.GATE, goal.ID)
+			g.WalkThrough()
+			model.Fix()
+			want := bellmanFord(graph, goal)[origin.ID]
+
+			first := PathTo(graph, model, origin)
+			if first == nil {
+				t.Fatalf("PathTo() got nil, want path")
+			}
+			if math.Abs(first.Value-want) > 1e-9 {
+				t.Errorf("PathTo().Value got %f, want %f", first.Value, want)
+			}
+
+			paths := Paths(origin, goal, 5, first)
+			if got := len(paths); got != 5 {
+				t.Fatalf("len(Paths()) got %d, want 5", got)
+			}
+			if got := Paths(origin, goal, 1, nil)[0].Value; math.Abs(got-want) > 1e-9 {
+				t.Errorf("Paths()[0].Value got %f, want %f", got, want)
+			}
+			for i, p := range paths {
+				if i > 0 && p.Value < paths[i-1].Value {
+					t.Errorf("Paths()[%d].Value got %f, want >= %f", i, p.Value, paths[i-1].Value)
+				}
+				if containsPath(paths[:i], p) {
+					t.Errorf("Paths()[%d] got duplicated path", i)
+				}
+				visited := map[*Node]bool{origin: true}
+				x := origin
+				for _, e := range p.Edges {
+					if e.FromNode != x {
+						t.Fatalf("Paths()[%d] got disconnected edge %v", i, e)
+					}
+					x = e.ToNode
+					if visited[x] {
+						t.Errorf("Paths()[%d] got loop at %v", i, x)
+					}
+					visited[x] = true
+				}
+				if x != goal {
+					t.Errorf("Paths()[%d] got end %v, want %v", i, x, goal)
+				}
+			}
+		})
+	}
+
+	t.Run("unreachable", func(t *testing.T) {
+		graph := genGrid(2, 1, 1)
+		origin, goal := graph.Nodes[entities.GATE][2], graph.Nodes[entities.GATE][1]
+		graph.RemoveEdge(entities.STEP, 1)
+		graph.RemoveEdge(entities.STEP, 2)
+		if got := len(Paths(origin, goal, 3, nil)); got != 0 {
+			t.Errorf("len(Paths()) got %d, want 0", got)
+		}
+	})
+}
@@ -11,6 +11,8 @@ type Model struct {
 	GoalIDs []uint
 	Nodes   map[entities.ModelType]map[uint]*Node
 	Edges   map[entities.ModelType]map[uint]*Edge
+	// costs calculates Value of Edge. nil means cost of Connectable as it is.
+	costs *CostModel
 }
 
 // NewModel creates instance or copies original if it is specified.
@@ -30,9 +32,9 @@ func NewModel(origin ...*Model) *Model {
 		for key := range origin.Edges {
 			edges[key] = make(map[uint]*Edge)
 		}
-		return &Model{goalIDs, nodes, edges}
+		return &Model{goalIDs, nodes, edges, origin.costs}
 	}
-	return &Model{[]uint{}, nodes, edges}
+	return &Model{[]uint{}, nodes, edges, nil}
 }
 
 // Export copies Nodes and Edges having same id.
@@ -78,6 +80,11 @@ func (m *Model) NumEdges() int {
 	return sum
 }
 
+// WithGoals returns Model sharing Nodes and Edges whose goals are replaced with specified ids.
+func (m *Model) WithGoals(ids []uint) *Model {
+	return &Model{ids, m.Nodes, m.Edges, m.costs}
+}
+
 // AddGoalID adds id as goal.
 func (m *Model) AddGoalID(id uint) {
 	m.GoalIDs = append(m.GoalIDs, id)
@@ -108,10 +115,49 @@ func (m *Model) FindOrCreateEdge(origin entities.Connectable) *Edge {
 	}
 	from, to := m.FindOrCreateNode(origin.From()), m.FindOrCreateNode(origin.To())
 	e := NewEdge(origin, from, to)
+	if m.costs != nil {
+		e.Value = m.costs.Cost(origin)
+	}
 	m.Edges[origin.B().Type()][origin.B().Idx()] = e
 	return e
 }
 
+// RemoveEdge deletes corresponding Edge and its connection with Nodes.
+// It returns removed Edge or nil if such Edge doesn't exist.
+func (m *Model) RemoveEdge(t entities.ModelType, id uint) *Edge {
+	e, ok := m.Edges[t][id]
+	if !ok {
+		return nil
+	}
+	e.FromNode.Out = removeEdge(e.FromNode.Out, e)
+	e.ToNode.In = removeEdge(e.ToNode.In, e)
+	delete(m.Edges[t], id)
+	return e
+}
+
+// RemoveNode deletes corresponding Node with connected Edges.
+// It returns removed Node or nil if such Node doesn't exist.
+func (m *Model) RemoveNode(t entities.ModelType, id uint) *Node {
+	n, ok := m.Nodes[t][id]
+	if !ok {
+		return nil
+	}
+	for _, e := range append(append([]*Edge{}, n.Out...), n.In...) {
+		m.RemoveEdge(e.ModelType, e.ID)
+	}
+	delete(m.Nodes[t], id)
+	return n
+}
+
+func removeEdge(es []*Edge, target *Edge) []*Edge {
+	for i, e := range es {
+		if e == target {
+			return append(es[:i], es[i+1:]...)
+		}
+	}
+	return es
+}
+
 // Fix discards no more using data.
 func (m *Model) Fix() {
 	for _, ns := range m.Nodes {
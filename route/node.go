@@ -1,9 +1,9 @@
 package route
 
 import (
+	"container/heap"
 	"fmt"
 	"math"
-	"sort"
 	"strings"
 
 	"github.com/yasshi2525/RushHour/entities"
@@ -16,7 +16,8 @@ type Digest struct {
 	Value     float64
 }
 
-// NodeQueue is open list for searching
+// NodeQueue is open list for searching.
+// It is binary heap ordered by Value and implements heap.Interface.
 type NodeQueue []*Node
 
 func (q NodeQueue) Len() int {
@@ -25,12 +26,40 @@ func (q NodeQueue) Len() int {
 
 func (q NodeQueue) Swap(i, j int) {
 	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
 }
 
 func (q NodeQueue) Less(i, j int) bool {
 	return q[i].Value < q[j].Value
 }
 
+// Push appends Node. Use heap.Push instead of calling it directly.
+func (q *NodeQueue) Push(x interface{}) {
+	n := x.(*Node)
+	n.index = len(*q)
+	*q = append(*q, n)
+}
+
+// Pop removes last Node. Use heap.Pop instead of calling it directly.
+func (q *NodeQueue) Pop() interface{} {
+	old := *q
+	n := old[len(old)-1]
+	old[len(old)-1] = nil
+	n.index = -1
+	*q = old[:len(old)-1]
+	return n
+}
+
+// update pushes Node or moves it to new position when its Value decreases in queue.
+func (q *NodeQueue) update(n *Node) {
+	if n.index >= 0 && n.index < len(*q) && (*q)[n.index] == n {
+		heap.Fix(q, n.index)
+	} else {
+		heap.Push(q, n)
+	}
+}
+
 // Node is digest of Relayable, Transportable for routing.
 // The chain of Node represents one route.
 type Node struct {
@@ -39,6 +68,8 @@ type Node struct {
 	ViaEdge *Edge
 	Out     []*Edge
 	In      []*Edge
+	// index is position in NodeQueue
+	index int
 }
 
 // NewNode returns instance
@@ -47,6 +78,7 @@ func NewNode(obj entities.Entity) *Node {
 		Digest: Digest{obj.B().Type(), obj.B().Idx(), math.MaxFloat64},
 		Out:    []*Edge{},
 		In:     []*Edge{},
+		index:  -1,
 	}
 }
 
@@ -57,28 +89,28 @@ func (n *Node) SameAs(oth entities.Entity) bool {
 
 // WalkThrough set distance towrards self to Value of connected Nodes.
 // Initial cost of connected Node must be max float64 value.
+// Each Node is settled once because queued Node is moved instead of being pushed again.
 func (n *Node) WalkThrough() {
-	var x, y *Node
-	var q NodeQueue = []*Node{}
+	q := &NodeQueue{}
 	for _, e := range n.In {
-		e.FromNode.Value = e.Value
-		e.FromNode.Via = n
-		q = append(q, e.FromNode)
+		if y := e.FromNode; y != n && e.Cost() < y.Value {
+			y.Value = e.Cost()
+			y.Via = n
+			q.update(y)
+		}
 	}
-	sort.Sort(q)
-
-	var v float64
-	for len(q) > 0 {
-		x, q = q[0], q[1:]
 
+	for q.Len() > 0 {
+		x := heap.Pop(q).(*Node)
 		for _, e := range x.In {
-			y = e.FromNode
-			v = x.Value + e.Cost()
-			if v < y.Value {
+			y := e.FromNode
+			if y == n {
+				continue
+			}
+			if v := x.Value + e.Cost(); v < y.Value {
 				y.Value = v
 				y.Via = x
-				q = append(q, y)
-				sort.Sort(q)
+				q.update(y)
 			}
 		}
 	}
@@ -103,6 +135,7 @@ func (n *Node) Export() *Node {
 		Digest: n.Digest,
 		Out:    []*Edge{},
 		In:     []*Edge{},
+		index:  -1,
 	}
 }
 
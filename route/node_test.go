@@ -0,0 +1,127 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/yasshi2525/RushHour/entities"
+)
+
+// genGrid creates w x h grid graph whose neighbors are connected in both directions with random cost.
+// Goal of result is top row.
+func genGrid(w int, h int, seed int64) *Model {
+	r := rand.New(rand.NewSource(seed))
+	m := NewModel()
+	m.Nodes[entities.GATE] = make(map[uint]*Node)
+	m.Edges[entities.STEP] = make(map[uint]*Edge)
+
+	id := func(x int, y int) uint {
+		return uint(y*w + x + 1)
+	}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			m.Nodes[entities.GATE][id(x, y)] = &Node{
+				Digest: Digest{entities.GATE, id(x, y), math.MaxFloat64},
+				Out:    []*Edge{},
+				In:     []*Edge{},
+				index:  -1,
+			}
+		}
+	}
+	var eid uint
+	connect := func(from uint, to uint) {
+		eid++
+		f, t := m.Nodes[entities.GATE][from], m.Nodes[entities.GATE][to]
+		e := &Edge{Digest{entities.STEP, eid, 1 + r.Float64()*9}, f, t}
+		f.Out = append(f.Out, e)
+		t.In = append(t.In, e)
+		m.Edges[entities.STEP][eid] = e
+	}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if x+1 < w {
+				connect(id(x, y), id(x+1, y))
+				connect(id(x+1, y), id(x, y))
+			}
+			if y+1 < h {
+				connect(id(x, y), id(x, y+1))
+				connect(id(x, y+1), id(x, y))
+			}
+		}
+	}
+	for x := 0; x < w; x++ {
+		m.AddGoalID(id(x, 0))
+	}
+	return m
+}
+
+// bellmanFord returns minimum distance to goal of each Node by relaxing all Edges repeatedly.
+func bellmanFord(m *Model, goal *Node) map[uint]float64 {
+	dist := make(map[uint]float64)
+	for id := range m.Nodes[entities.GATE] {
+		dist[id] = math.MaxFloat64
+	}
+	dist[goal.ID] = 0
+	for i := 0; i < m.NumNodes(); i++ {
+		for _, e := range m.Edges[entities.STEP] {
+			if d := dist[e.ToNode.ID]; d < math.MaxFloat64 && d+e.Cost() < dist[e.FromNode.ID] {
+				dist[e.FromNode.ID] = d + e.Cost()
+			}
+		}
+	}
+	return dist
+}
+
+func TestWalkThrough(t *testing.T) {
+	for _, seed := range []int64{1, 2, 3} {
+		t.Run(fmt.Sprintf("seed%d", seed), func(t *testing.T) {
+			template := genGrid(8, 8, seed)
+			model, goal := template.ExportWith(entities.GATE, 1)
+			goal.WalkThrough()
+			want := bellmanFord(model, goal)
+
+			for id, n := range model.Nodes[entities.GATE] {
+				if n == goal {
+					continue
+				}
+				if math.Abs(n.Value-want[id]) > 1e-9 {
+					t.Errorf("Gate(%d).Value got %f, want %f", id, n.Value, want[id])
+				}
+				if n.Via == nil {
+					t.Errorf("Gate(%d).Via got nil, want not nil", id)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkWalkThrough(b *testing.B) {
+	for _, size := range []int{100, 200} {
+		template := genGrid(size, size, 1)
+		b.Run(fmt.Sprintf("nodes=%d", size*size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				_, goal := template.ExportWith(entities.GATE, 1)
+				b.StartTimer()
+				goal.WalkThrough()
+			}
+		})
+	}
+}
+
+func BenchmarkSearch(b *testing.B) {
+	template := genGrid(100, 100, 1)
+	template.GoalIDs = template.GoalIDs[:10]
+	for _, worker := range []int{1, 4} {
+		b.Run(fmt.Sprintf("nodes=10000,goals=10,worker=%d", worker), func(b *testing.B) {
+			p := NewPool(worker)
+			defer p.Stop()
+			for i := 0; i < b.N; i++ {
+				p.Search(context.Background(), entities.GATE, Background, template)
+			}
+		})
+	}
+}
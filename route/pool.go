@@ -0,0 +1,209 @@
+package route
+
+import (
+	"context"
+	"runtime"
+	"sync"
+
+	"github.com/yasshi2525/RushHour/entities"
+)
+
+// Priority represents which search runs first when workers are busy.
+type Priority int
+
+const (
+	// Interactive is search requested by Player such as refreshing tracks and transports.
+	Interactive Priority = iota
+	// Background is search for routing Human.
+	Background
+	numPriority
+)
+
+// queueRatio is the number of tasks each worker can accept before sender is blocked.
+const queueRatio = 4
+
+// task is search for one goal.
+type task struct {
+	ctx    context.Context
+	target entities.ModelType
+	goalID uint
+	model  *Model
+	done   func(*Model)
+}
+
+// Pool is bounded, long-lived workers shared by all route searches.
+// Interactive tasks are taken before Background ones.
+// Sender is blocked while queue of its Priority is full.
+type Pool struct {
+	queues [numPriority]chan *task
+	quit   chan struct{}
+	wg     sync.WaitGroup
+	// mu makes Stop wait for senders so that no task is queued after queues are drained
+	mu      sync.RWMutex
+	stopped bool
+}
+
+// NewPool starts specified number of workers.
+func NewPool(worker int) *Pool {
+	if worker < 1 {
+		worker = 1
+	}
+	p := &Pool{quit: make(chan struct{})}
+	for i := range p.queues {
+		p.queues[i] = make(chan *task, worker*queueRatio)
+	}
+	p.wg.Add(worker)
+	for i := 0; i < worker; i++ {
+		go p.work()
+	}
+	return p
+}
+
+// work executes tasks until Pool stops.
+func (p *Pool) work() {
+	defer p.wg.Done()
+	for {
+		// prefer Interactive
+		select {
+		case t := <-p.queues[Interactive]:
+			t.run()
+			continue
+		default:
+		}
+		select {
+		case t := <-p.queues[Interactive]:
+			t.run()
+		case t := <-p.queues[Background]:
+			t.run()
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+// run searches route to goal unless it is canceled.
+func (t *task) run() {
+	select {
+	case <-t.ctx.Done():
+		t.done(nil)
+	default:
+		model, goal := t.model.ExportWith(t.target, t.goalID)
+		goal.WalkThrough()
+		model.Fix()
+		t.done(model)
+	}
+}
+
+// Search calculates minimum distance of route for each goal of model with workers.
+// It returns false when search is canceled.
+func (p *Pool) Search(ctx context.Context, t entities.ModelType, prio Priority, model *Model) (*Payload, bool) {
+	payload := &Payload{make(map[uint]*Model), 0, len(model.GoalIDs)}
+	mu := &sync.Mutex{}
+	wg := &sync.WaitGroup{}
+	done := func(m *Model) {
+		defer wg.Done()
+		if m == nil {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		payload.Route[m.GoalIDs[0]] = m
+		payload.Processed++
+	}
+
+	for _, goalID := range model.GoalIDs {
+		wg.Add(1)
+		if !p.enqueue(ctx, prio, &task{ctx, t, goalID, model.WithGoals([]uint{goalID}), done}) {
+			wg.Done()
+		}
+	}
+	wg.Wait()
+	return payload, payload.IsOK()
+}
+
+// enqueue passes task to workers. It returns false when task is not accepted.
+func (p *Pool) enqueue(ctx context.Context, prio Priority, x *task) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.stopped {
+		return false
+	}
+	select {
+	case p.queues[prio] <- x:
+		return true
+	case <-ctx.Done():
+		return false
+	case <-p.quit:
+		return false
+	}
+}
+
+// Stop terminates workers after they finish current tasks.
+// Queued tasks are regarded as canceled.
+func (p *Pool) Stop() {
+	// quit releases senders blocked by full queue before waiting for them
+	close(p.quit)
+	p.mu.Lock()
+	p.stopped = true
+	p.mu.Unlock()
+	p.wg.Wait()
+	for _, q := range p.queues {
+		for len(q) > 0 {
+			(<-q).done(nil)
+		}
+	}
+}
+
+var pool *Pool
+var poolStopped bool
+var muPool sync.Mutex
+
+// InitPool replaces shared Pool with new one having specified number of workers.
+func InitPool(worker int) {
+	muPool.Lock()
+	defer muPool.Unlock()
+	if pool != nil {
+		pool.Stop()
+	}
+	pool = NewPool(worker)
+	poolStopped = false
+}
+
+// StopPool terminates shared Pool. Search fails until InitPool is called.
+func StopPool() {
+	muPool.Lock()
+	defer muPool.Unlock()
+	if pool != nil {
+		pool.Stop()
+		pool = nil
+	}
+	poolStopped = true
+}
+
+// PoolStopped returns whether shared Pool is terminated by StopPool.
+func PoolStopped() bool {
+	muPool.Lock()
+	defer muPool.Unlock()
+	return poolStopped
+}
+
+// sharedPool returns shared Pool. It starts workers as many as CPU unless InitPool is called.
+// It returns nil after StopPool.
+func sharedPool() *Pool {
+	muPool.Lock()
+	defer muPool.Unlock()
+	if pool == nil && !poolStopped {
+		pool = NewPool(runtime.NumCPU())
+	}
+	return pool
+}
+
+// Search calculates minimum distance of route for specified goal with shared Pool.
+// It returns false when shared Pool is stopped.
+func Search(ctx context.Context, t entities.ModelType, prio Priority, model *Model) (*Payload, bool) {
+	p := sharedPool()
+	if p == nil {
+		return nil, false
+	}
+	return p.Search(ctx, t, prio, model)
+}
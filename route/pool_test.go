@@ -0,0 +1,106 @@
+package route
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yasshi2525/RushHour/entities"
+)
+
+func TestPool(t *testing.T) {
+	t.Run("priority", func(t *testing.T) {
+		template := genGrid(2, 2, 1)
+		p := &Pool{quit: make(chan struct{})}
+		for i := range p.queues {
+			p.queues[i] = make(chan *task, 4)
+		}
+
+		mu := &sync.Mutex{}
+		wg := &sync.WaitGroup{}
+		order := []Priority{}
+		enqueue := func(prio Priority) {
+			wg.Add(1)
+			p.queues[prio] <- &task{context.Background(), entities.GATE, 1, template, func(*Model) {
+				mu.Lock()
+				defer mu.Unlock()
+				order = append(order, prio)
+				wg.Done()
+			}}
+		}
+		enqueue(Background)
+		enqueue(Background)
+		enqueue(Interactive)
+		enqueue(Interactive)
+
+		p.wg.Add(1)
+		go p.work()
+		wg.Wait()
+		p.Stop()
+
+		want := []Priority{Interactive, Interactive, Background, Background}
+		for i := range want {
+			if order[i] != want[i] {
+				t.Errorf("order got %v, want %v", order, want)
+				break
+			}
+		}
+	})
+
+	t.Run("search", func(t *testing.T) {
+		p := NewPool(2)
+		defer p.Stop()
+		template := genGrid(4, 4, 1)
+		payload, ok := p.Search(context.Background(), entities.GATE, Background, template)
+		if !ok {
+			t.Errorf("Search() got not ok, want ok")
+		}
+		if got, want := len(payload.Route), len(template.GoalIDs); got != want {
+			t.Errorf("len(Route) got %d, want %d", got, want)
+		}
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		// senders racing with Stop must not wait for task nobody runs
+		for i := 0; i < 100; i++ {
+			p := NewPool(1)
+			done := make(chan bool)
+			for j := 0; j < 4; j++ {
+				go func() {
+					p.Search(context.Background(), entities.GATE, Background, genGrid(4, 4, 1))
+					done <- true
+				}()
+			}
+			p.Stop()
+			for j := 0; j < 4; j++ {
+				select {
+				case <-done:
+				case <-time.After(5 * time.Second):
+					t.Fatalf("Search() after Stop() got blocked")
+				}
+			}
+		}
+	})
+
+	t.Run("shared", func(t *testing.T) {
+		StopPool()
+		if _, ok := Search(context.Background(), entities.GATE, Background, genGrid(2, 2, 1)); ok || !PoolStopped() {
+			t.Errorf("Search() after StopPool() got ok, want canceled")
+		}
+		InitPool(1)
+		if _, ok := Search(context.Background(), entities.GATE, Background, genGrid(2, 2, 1)); !ok || PoolStopped() {
+			t.Errorf("Search() after InitPool() got canceled, want ok")
+		}
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		p := NewPool(1)
+		defer p.Stop()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, ok := p.Search(ctx, entities.GATE, Background, genGrid(4, 4, 1)); ok {
+			t.Errorf("Search() got ok, want canceled")
+		}
+	})
+}
@@ -6,28 +6,33 @@ import (
 	"github.com/yasshi2525/RushHour/entities"
 )
 
-// RefreshTracks set minimum distance trace on specified rail.
-func RefreshTracks(o *entities.Player, parallel int) map[uint]*Model {
-	o.ClearTracks()
-	template := scanRail(o)
-
-	payload, _ := Search(context.Background(), entities.RAILNODE, parallel, template)
-
-	for destID, model := range payload.Route {
-		for deptID, dept := range model.Nodes[entities.RAILNODE] {
-			if dept.ViaEdge != nil {
-				eid := dept.ViaEdge.ID
-				tracks := o.RailNodes[deptID].Tracks
+// RefreshTracks set minimum distance route on rail of specified Player.
+func RefreshTracks(o *entities.Player) map[uint]*Model {
+	j := NewTracksJob(o)
+	j.Search(context.Background())
+	return j.Apply()
+}
 
-				if _, ok := tracks[eid]; !ok {
-					tracks[eid] = make(map[uint]bool)
+// NewTracksJob scans rail of Player for refreshing its tracks.
+func NewTracksJob(o *entities.Player) *Job {
+	return &Job{template: scanRail(o), apply: func(route map[uint]*Model) {
+		o.ClearTracks()
+		for destID, model := range route {
+			for deptID, dept := range model.Nodes[entities.RAILNODE] {
+				rn, ok := o.RailNodes[deptID]
+				if ok && dept.ViaEdge != nil {
+					eid := dept.ViaEdge.ID
+					tracks := rn.Tracks
+
+					if _, ok := tracks[eid]; !ok {
+						tracks[eid] = make(map[uint]bool)
+					}
+					tracks[eid][destID] = true
 				}
-				tracks[eid][destID] = true
 			}
 		}
-	}
-	o.ReRouting = false
-	return payload.Route
+		o.ReRouting = false
+	}}
 }
 
 func scanRail(o *entities.Player) *Model {
@@ -7,42 +7,60 @@ import (
 )
 
 // RefreshTransports set minimum distance route on specified rail line.
-func RefreshTransports(l *entities.RailLine, parallel int) map[uint]*Model {
-	l.ClearTransports()
-	if !l.IsRing() || len(l.Trains) == 0 {
-		return nil
-	}
-	template := scanRailLine(l)
-
-	payload, _ := Search(context.Background(), entities.PLATFORM, parallel, template)
+func RefreshTransports(l *entities.RailLine) map[uint]*Model {
+	j := NewTransportsJob(l)
+	j.Search(context.Background())
+	return j.Apply()
+}
 
-	for destID, model := range payload.Route {
-		for deptID, dept := range model.Nodes[entities.PLATFORM] {
-			if dept.ViaEdge != nil {
-				l.M.NewTransport(
-					l.Stops[deptID],          // from
-					l.Stops[destID],          // to
-					l.Tasks[dept.ViaEdge.ID], // via
-					dept.Value)               // cost
-			} // ViaEdge = nil means cannot go to dest from dept by following line
+// NewTransportsJob scans specified rail line for refreshing its Transports.
+// Transports are only cleared unless RailLine is ringed and has Train.
+func NewTransportsJob(l *entities.RailLine) *Job {
+	j := &Job{}
+	var stops map[uint]*entities.Platform
+	if l.IsRing() && len(l.Trains) > 0 {
+		j.template, stops = scanRailLine(l)
+	}
+	j.apply = func(route map[uint]*Model) {
+		l.ClearTransports()
+		for destID, model := range route {
+			for deptID, dept := range model.Nodes[entities.RAILNODE] {
+				// skip goal itself because ring returns to it
+				from, ok := stops[deptID]
+				if !ok || deptID == destID {
+					continue
+				}
+				if dept.ViaEdge != nil {
+					l.M.NewTransport(
+						from,                     // from
+						stops[destID],            // to
+						l.Tasks[dept.ViaEdge.ID], // via
+						dept.Value)               // cost
+				} // ViaEdge = nil means cannot go to dest from dept by following line
+			}
 		}
+		l.ReRouting = false
 	}
-	l.ReRouting = false
-	return payload.Route
+	return j
 }
 
-func scanRailLine(l *entities.RailLine) *Model {
+// scanRailLine returns template whose goals are RailNodes under Platforms
+// because LineTask connects RailNodes.
+// It also returns Platform for each id of RailNode.
+func scanRailLine(l *entities.RailLine) (*Model, map[uint]*entities.Platform) {
 	model := NewModel()
+	stops := make(map[uint]*entities.Platform)
 
 	// gen goalid
 	for _, p := range l.Stops {
-		model.AddGoalID(p.ID)
-		model.FindOrCreateNode(p)
+		model.AddGoalID(p.OnRailNode.ID)
+		model.FindOrCreateNode(p.OnRailNode)
+		stops[p.OnRailNode.ID] = p
 	}
 
 	// gen nodes, edges
 	for _, lt := range l.Tasks {
 		model.FindOrCreateEdge(lt)
 	}
-	return model
+	return model, stops
 }
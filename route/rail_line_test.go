@@ -0,0 +1,45 @@
+package route
+
+import (
+	"testing"
+
+	"github.com/yasshi2525/RushHour/auth"
+	"github.com/yasshi2525/RushHour/config"
+	"github.com/yasshi2525/RushHour/entities"
+)
+
+func TestRefreshTransports(t *testing.T) {
+	a, _ := auth.GetAuther(config.CnfAuth{Key: "----------------"})
+	c := config.CnfEntity{
+		MaxScale: 16,
+		Train:    config.CnfTrain{Speed: 10, Capacity: 1, Mobility: 1, Slowness: 0.5},
+	}
+	m := entities.NewModel(c, a)
+	o := m.NewPlayer()
+	n0 := m.NewRailNode(o, 0, 0)
+	n1, e01 := n0.Extend(200, 0)
+	p0 := m.NewPlatform(n0, m.NewGate(m.NewStation(o)))
+	p1 := m.NewPlatform(n1, m.NewGate(m.NewStation(o)))
+
+	l := m.NewRailLine(o)
+	head := m.NewLineTaskDept(l, p0)
+	tail := m.NewLineTask(l, e01, head)
+	tail = m.NewLineTaskDept(l, p1, tail)
+	tail = m.NewLineTask(l, e01.Reverse, tail)
+	tail.SetNext(head)
+	m.NewTrain(o, "test").SetTask(head)
+
+	RefreshTransports(l)
+	RefreshTransports(l)
+
+	if got := len(m.Transports); got != 2 {
+		t.Errorf("len(Transports) got %d, want 2", got)
+	}
+	for _, x := range []*entities.Transport{p0.Transports[p1.ID], p1.Transports[p0.ID]} {
+		if x == nil {
+			t.Errorf("Transport got nil, want p0 <=> p1")
+		} else if x.Via.RailLine != l || x.Value <= 0 {
+			t.Errorf("Transport got %v, want via %v", x, l)
+		}
+	}
+}
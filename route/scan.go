@@ -6,10 +6,17 @@ import (
 	"github.com/yasshi2525/RushHour/entities"
 )
 
-// Scan extracts Step for Human informations.
-func Scan(ctx context.Context, model *entities.Model) (*Model, bool) {
+// GoalTypes is list of destination Human heads for.
+// Human goes to Company and returns to Residence.
+var GoalTypes = []entities.ModelType{entities.COMPANY, entities.RESIDENCE}
+
+// Scan extracts Step and Transport for Human informations.
+// Human and its Step are out of target because Human is routed on demand.
+// Cost of Edge is calculated by specified CostModel.
+// Goals of result is empty, so call WithGoals for each type of GoalTypes before searching.
+func Scan(ctx context.Context, model *entities.Model, costs *CostModel) (*Model, bool) {
 	result := NewModel()
-	result.GoalIDs = model.Ids(entities.COMPANY)
+	result.costs = costs
 
 	if !genNodes(ctx, result, model) {
 		return result, false
@@ -20,7 +27,7 @@ func Scan(ctx context.Context, model *entities.Model) (*Model, bool) {
 
 func genNodes(ctx context.Context, result *Model, model *entities.Model) bool {
 	for _, res := range entities.TypeList {
-		if _, ok := res.Obj(model).(entities.Relayable); ok {
+		if _, ok := res.Obj(model).(entities.Relayable); ok && res != entities.HUMAN {
 			select {
 			case <-ctx.Done():
 				return false
@@ -48,7 +55,9 @@ func genEdges(ctx context.Context, result *Model, model *entities.Model) bool {
 		case <-ctx.Done():
 			return false
 		default:
-			result.FindOrCreateEdge(s)
+			if _, ok := s.FromNode.(*entities.Human); !ok {
+				result.FindOrCreateEdge(s)
+			}
 		}
 	}
 	return true
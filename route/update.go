@@ -0,0 +1,252 @@
+package route
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/yasshi2525/RushHour/entities"
+)
+
+// Update applies changes recorded in Journal to graph and minimum distance route of each goal.
+// Only Nodes whose route is affected by the changes are recalculated.
+// Route for new goal is searched from scratch and route for removed goal is discarded.
+// It returns the number of goals whose route was changed.
+func Update(graph *Model, payloads map[entities.ModelType]*Payload, j *entities.Journal) int {
+	removed, added := applyJournal(graph, j)
+
+	var cnt int
+	for _, t := range GoalTypes {
+		payload, ok := payloads[t]
+		if !ok {
+			continue
+		}
+		for id := range j.Removed[t] {
+			delete(payload.Route, id)
+		}
+		for id, model := range payload.Route {
+			syncRoute(graph, model, removed, added)
+			if updateRoute(graph, model, model.Nodes[t][id], removed, added) {
+				cnt++
+			}
+		}
+		for id := range j.Added[t] {
+			if _, ok := payload.Route[id]; ok {
+				continue
+			}
+			model, goal := graph.ExportWith(t, id)
+			if goal == nil {
+				continue
+			}
+			goal.WalkThrough()
+			model.Fix()
+			payload.Route[id] = model
+			cnt++
+		}
+	}
+	return cnt
+}
+
+// applyJournal reflects changes to graph.
+// It returns removed Edges and added Edges of graph.
+func applyJournal(graph *Model, j *entities.Journal) ([]*Edge, []*Edge) {
+	removed, added := []*Edge{}, []*Edge{}
+	for res, ids := range j.Removed {
+		if !res.IsConnectable() {
+			continue
+		}
+		for id := range ids {
+			if e := graph.RemoveEdge(res, id); e != nil {
+				removed = append(removed, e)
+			}
+		}
+	}
+	for res, ids := range j.Removed {
+		if !res.IsRelayable() {
+			continue
+		}
+		for id := range ids {
+			if n, ok := graph.Nodes[res][id]; ok {
+				removed = append(removed, n.Out...)
+				removed = append(removed, n.In...)
+				graph.RemoveNode(res, id)
+			}
+		}
+	}
+	for res, objs := range j.Added {
+		if !res.IsRelayable() {
+			continue
+		}
+		for _, obj := range objs {
+			graph.FindOrCreateNode(obj)
+		}
+	}
+	for res, objs := range j.Added {
+		if !res.IsConnectable() {
+			continue
+		}
+		for id, obj := range objs {
+			if _, ok := graph.Edges[res][id]; !ok {
+				added = append(added, graph.FindOrCreateEdge(obj.(entities.Connectable)))
+			}
+		}
+	}
+	return removed, added
+}
+
+// Reweight recalculates cost of Edges of graph with its CostModel and updates route of each goal.
+// Step and Transport must be same as graph, so call Update before it.
+// It returns the number of goals whose route was changed.
+func Reweight(graph *Model, payloads map[entities.ModelType]*Payload, model *entities.Model) int {
+	increased, decreased := []*Edge{}, []*Edge{}
+	for res, es := range graph.Edges {
+		for id, e := range es {
+			var obj entities.Connectable
+			switch res {
+			case entities.STEP:
+				obj = model.Steps[id]
+			case entities.TRANSPORT:
+				obj = model.Transports[id]
+			}
+			if obj == nil {
+				continue
+			}
+			v := graph.costs.Cost(obj)
+			if v > e.Value {
+				increased = append(increased, e)
+			} else if v < e.Value {
+				decreased = append(decreased, e)
+			}
+			e.Value = v
+		}
+	}
+	if len(increased) == 0 && len(decreased) == 0 {
+		return 0
+	}
+
+	var cnt int
+	for _, t := range GoalTypes {
+		payload, ok := payloads[t]
+		if !ok {
+			continue
+		}
+		for id, model := range payload.Route {
+			for _, e := range append(append([]*Edge{}, increased...), decreased...) {
+				if x, ok := model.Edges[e.ModelType][e.ID]; ok {
+					x.Value = e.Value
+				}
+			}
+			if updateRoute(graph, model, model.Nodes[t][id], increased, decreased) {
+				cnt++
+			}
+		}
+	}
+	return cnt
+}
+
+// updateRoute makes route to goal consistent with graph.
+// Nodes routing via invalid Edge, which is removed or gets longer, are reset and searched again,
+// then Nodes which get shorter by relaxed Edges, which is added or gets shorter, are relaxed.
+// It returns whether any Node changes its route or not.
+func updateRoute(graph *Model, model *Model, goal *Node, invalid []*Edge, relaxed []*Edge) bool {
+	q := &NodeQueue{}
+	affected := invalidate(graph, model, goal, invalid)
+	for _, x := range affected {
+		for _, e := range graph.Nodes[x.ModelType][x.ID].Out {
+			relax(model, goal, e, q)
+		}
+	}
+	changed := len(affected) > 0
+	for _, e := range relaxed {
+		changed = relax(model, goal, e, q) || changed
+	}
+
+	for q.Len() > 0 {
+		x := heap.Pop(q).(*Node)
+		for _, e := range graph.Nodes[x.ModelType][x.ID].In {
+			relax(model, goal, e, q)
+		}
+	}
+	return changed
+}
+
+// syncRoute adds and removes Nodes and Edges of model as same as graph.
+// Edges of model don't have connection because model has been already fixed.
+func syncRoute(graph *Model, model *Model, removed []*Edge, added []*Edge) {
+	for _, e := range removed {
+		delete(model.Edges[e.ModelType], e.ID)
+		for _, n := range []*Node{e.FromNode, e.ToNode} {
+			if _, ok := graph.Nodes[n.ModelType][n.ID]; !ok {
+				delete(model.Nodes[n.ModelType], n.ID)
+			}
+		}
+	}
+	for _, e := range added {
+		from, to := findOrExport(model, e.FromNode), findOrExport(model, e.ToNode)
+		if _, ok := model.Edges[e.ModelType]; !ok {
+			model.Edges[e.ModelType] = make(map[uint]*Edge)
+		}
+		model.Edges[e.ModelType][e.ID] = &Edge{e.Digest, from, to}
+	}
+}
+
+// invalidate resets Nodes whose route passes through invalid Edges.
+// It returns reset Nodes.
+func invalidate(graph *Model, model *Model, goal *Node, invalid []*Edge) []*Node {
+	affected := []*Node{}
+	for _, e := range invalid {
+		if n, ok := model.Nodes[e.FromNode.ModelType][e.FromNode.ID]; ok && isVia(n, e) {
+			affected = append(affected, n)
+		}
+	}
+	for i := 0; i < len(affected); i++ {
+		x := affected[i]
+		for _, e := range graph.Nodes[x.ModelType][x.ID].In {
+			if y := model.Nodes[e.FromNode.ModelType][e.FromNode.ID]; y != goal && isVia(y, e) {
+				affected = append(affected, y)
+			}
+		}
+		x.Value, x.Via, x.ViaEdge = math.MaxFloat64, nil, nil
+	}
+	return affected
+}
+
+// relax shortens route of the origin of specified Edge of graph if it can.
+// Shortened Node is pushed to queue.
+func relax(model *Model, goal *Node, e *Edge, q *NodeQueue) bool {
+	x := model.Nodes[e.FromNode.ModelType][e.FromNode.ID]
+	y := model.Nodes[e.ToNode.ModelType][e.ToNode.ID]
+	if x == goal {
+		return false
+	}
+	d := y.Value
+	if y == goal {
+		d = 0
+	} else if y.Via == nil {
+		return false
+	}
+	if v := d + e.Cost(); v < x.Value {
+		x.Value, x.Via, x.ViaEdge = v, y, model.Edges[e.ModelType][e.ID]
+		q.update(x)
+		return true
+	}
+	return false
+}
+
+// isVia returns whether Node routes via specified Edge or not.
+func isVia(n *Node, e *Edge) bool {
+	return n.ViaEdge != nil && n.ViaEdge.ModelType == e.ModelType && n.ViaEdge.ID == e.ID
+}
+
+// findOrExport returns Node of model corresponding to Node of graph.
+func findOrExport(model *Model, n *Node) *Node {
+	if _, ok := model.Nodes[n.ModelType]; !ok {
+		model.Nodes[n.ModelType] = make(map[uint]*Node)
+	}
+	if x, ok := model.Nodes[n.ModelType][n.ID]; ok {
+		return x
+	}
+	x := n.Export()
+	x.In, x.Out = nil, nil
+	model.Nodes[n.ModelType][n.ID] = x
+	return x
+}
@@ -0,0 +1,180 @@
+package route
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/yasshi2525/RushHour/auth"
+	"github.com/yasshi2525/RushHour/config"
+	"github.com/yasshi2525/RushHour/entities"
+)
+
+func TestUpdate(t *testing.T) {
+	a, _ := auth.GetAuther(config.CnfAuth{Key: "----------------"})
+	c := config.CnfEntity{
+		MaxScale: 16,
+		Human:    config.CnfHuman{Speed: 1},
+	}
+
+	// prepare builds r -> (p0) ... (p1) -> c without Transport and searches route from scratch
+	prepare := func() (*entities.Model, *Model, map[entities.ModelType]*Payload, *entities.Platform, *entities.Platform) {
+		m := entities.NewModel(c, a)
+		o := m.NewPlayer()
+		m.NewResidence(0, 0)
+		m.NewCompany(100, 0)
+		st0, st1 := m.NewStation(o), m.NewStation(o)
+		p0 := m.NewPlatform(m.NewRailNode(o, 0, 0), m.NewGate(st0))
+		p1 := m.NewPlatform(m.NewRailNode(o, 100, 0), m.NewGate(st1))
+
+		graph, payloads := search(m)
+		m.TakeJournal()
+		return m, graph, payloads, p0, p1
+	}
+
+	// assertSame compares route updated incrementally with route searched from scratch
+	assertSame := func(t *testing.T, m *entities.Model, payloads map[entities.ModelType]*Payload, costs ...*CostModel) {
+		t.Helper()
+		_, want := search(m, costs...)
+		for _, res := range GoalTypes {
+			if got, want := len(payloads[res].Route), len(want[res].Route); got != want {
+				t.Errorf("len(%v) got %d, want %d", res, got, want)
+			}
+			for id, wantModel := range want[res].Route {
+				gotModel, ok := payloads[res].Route[id]
+				if !ok {
+					t.Errorf("%v(%d) got no route, want %v", res, id, wantModel)
+					continue
+				}
+				for nres, ns := range wantModel.Nodes {
+					for nid, wantNode := range ns {
+						gotNode, ok := gotModel.Nodes[nres][nid]
+						if !ok {
+							t.Errorf("%v(%d).%v(%d) got no node, want %v", res, id, nres, nid, wantNode)
+						} else if wantNode.Via != nil && math.Abs(gotNode.Value-wantNode.Value) > 1e-6 {
+							t.Errorf("%v(%d).%v(%d) got %v, want %v", res, id, nres, nid, gotNode, wantNode)
+						} else if (gotNode.Via == nil) != (wantNode.Via == nil) {
+							t.Errorf("%v(%d).%v(%d).Via got %v, want %v", res, id, nres, nid, gotNode.Via, wantNode.Via)
+						}
+					}
+				}
+				if got, want := gotModel.NumNodes(), wantModel.NumNodes(); got != want {
+					t.Errorf("%v(%d).NumNodes got %d, want %d", res, id, got, want)
+				}
+			}
+		}
+	}
+
+	t.Run("add", func(t *testing.T) {
+		m, graph, payloads, p0, p1 := prepare()
+		m.NewTransport(p0, p1, nil, 10)
+		m.NewTransport(p1, p0, nil, 10)
+
+		if got := Update(graph, payloads, m.TakeJournal()); got != 2 {
+			t.Errorf("Update() got %d, want 2", got)
+		}
+		assertSame(t, m, payloads)
+		for _, r := range m.Residences {
+			for _, cp := range m.Companies {
+				if got := payloads[entities.COMPANY].Route[cp.ID].Nodes[entities.RESIDENCE][r.ID].Value; got != 10 {
+					t.Errorf("r -> c got %f, want 10", got)
+				}
+			}
+		}
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		m, graph, payloads, p0, p1 := prepare()
+		x := m.NewTransport(p0, p1, nil, 10)
+		Update(graph, payloads, m.TakeJournal())
+		m.Delete(x)
+
+		Update(graph, payloads, m.TakeJournal())
+		assertSame(t, m, payloads)
+		for _, r := range m.Residences {
+			for _, cp := range m.Companies {
+				if got := payloads[entities.COMPANY].Route[cp.ID].Nodes[entities.RESIDENCE][r.ID].Value; got != 100 {
+					t.Errorf("r -> c got %f, want 100", got)
+				}
+			}
+		}
+	})
+
+	t.Run("goal", func(t *testing.T) {
+		m, graph, payloads, p0, p1 := prepare()
+		m.NewTransport(p0, p1, nil, 10)
+		m.NewCompany(50, 50)
+		for _, r := range m.Residences {
+			r.Delete()
+		}
+		m.NewResidence(100, 100)
+
+		Update(graph, payloads, m.TakeJournal())
+		assertSame(t, m, payloads)
+	})
+
+	t.Run("station", func(t *testing.T) {
+		m, graph, payloads, p0, p1 := prepare()
+		m.NewTransport(p0, p1, nil, 10)
+		Update(graph, payloads, m.TakeJournal())
+		p0.WithGate.InStation.Delete()
+
+		Update(graph, payloads, m.TakeJournal())
+		assertSame(t, m, payloads)
+	})
+
+	t.Run("reweight", func(t *testing.T) {
+		c := c
+		c.Platform.Capacity = 1
+		m := entities.NewModel(c, a)
+		o := m.NewPlayer()
+		r := m.NewResidence(0, 0)
+		cp := m.NewCompany(100, 0)
+		// r -> (p0) -> (p1) -> c costs 10, r -> (q0) -> (q1) -> c costs 12
+		platform := func(x float64, y float64) *entities.Platform {
+			return m.NewPlatform(m.NewRailNode(o, x, y), m.NewGate(m.NewStation(o)))
+		}
+		p0, p1, q0, q1 := platform(0, 0), platform(100, 0), platform(0, 1), platform(100, 1)
+		m.NewTransport(p0, p1, nil, 10)
+		m.NewTransport(q0, q1, nil, 10)
+		costs := &CostModel{Crowd: 60}
+		graph, payloads := search(m, costs)
+		m.TakeJournal()
+
+		value := func() float64 {
+			return payloads[entities.COMPANY].Route[cp.ID].Nodes[entities.RESIDENCE][r.ID].Value
+		}
+		if got := value(); got != 10 {
+			t.Errorf("r -> c got %f, want 10", got)
+		}
+
+		p0.Occupied = 1
+		if got := Reweight(graph, payloads, m); got != 1 {
+			t.Errorf("Reweight() got %d, want 1", got)
+		}
+		assertSame(t, m, payloads, costs)
+		if got := value(); got != 12 {
+			t.Errorf("crowded r -> c got %f, want 12", got)
+		}
+
+		p0.Occupied = 0
+		Reweight(graph, payloads, m)
+		assertSame(t, m, payloads, costs)
+		if got := value(); got != 10 {
+			t.Errorf("uncrowded r -> c got %f, want 10", got)
+		}
+	})
+}
+
+func search(m *entities.Model, costs ...*CostModel) (*Model, map[entities.ModelType]*Payload) {
+	var cm *CostModel
+	if len(costs) > 0 {
+		cm = costs[0]
+	}
+	graph, _ := Scan(context.Background(), m, cm)
+	payloads := make(map[entities.ModelType]*Payload)
+	for _, res := range GoalTypes {
+		payloads[res], _ = Search(context.Background(), res, Background, graph.WithGoals(m.Ids(res)))
+	}
+	return graph, payloads
+}
@@ -1,17 +1,112 @@
 package services
 
 import (
-	"fmt"
 	"log"
+	"reflect"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/yasshi2525/RushHour/entities"
-
-	"github.com/jinzhu/gorm"
+	"github.com/yasshi2525/RushHour/storage"
 )
 
 var backupTicker *time.Ticker
 
+// muBackupQueue protects backupQueue, backupStats and backupTicks.
+// Other locks must not be acquired while holding it.
+var muBackupQueue sync.Mutex
+
+// backupQueue is changes waiting for writing. It includes changes failed to write before.
+var backupQueue *backupBatch
+
+// backupStats is metrics of backup
+var backupStats BackupStats
+
+// backupTicks is Ticks of GameClock captured last.
+var backupTicks uint64
+
+// muBackupWriter serializes writing to database.
+// Writer never acquires MuModel, so it can be acquired with lock of MuModel.
+var muBackupWriter sync.Mutex
+
+// BackupStats represents metrics of backup
+type BackupStats struct {
+	// Succeeded is the number of backups written to database
+	Succeeded int `json:"succeeded"`
+	// Failed is the number of backups which failed after all retries
+	Failed int `json:"failed"`
+	// Retried is the number of retries
+	Retried int `json:"retried"`
+	// Pending is the number of records waiting for next backup
+	Pending int `json:"pending"`
+	// LastSuccess is when backup succeeded last
+	LastSuccess time.Time `json:"last_success"`
+	// LastError is reason of last failure. It is cleared after success.
+	LastError string `json:"last_error,omitempty"`
+}
+
+// backupBatch is changes captured under lock and written without lock.
+type backupBatch struct {
+	// records is copy of changed entities
+	records map[entities.ModelType]map[uint]entities.Persistable
+	deletes map[entities.ModelType][]uint
+	logs    []*OpLog
+	scores  []*Score
+	// clock is progress of GameClock. nil means unchanged.
+	clock *ClockState
+}
+
+func newBackupBatch() *backupBatch {
+	b := &backupBatch{
+		records: make(map[entities.ModelType]map[uint]entities.Persistable),
+		deletes: make(map[entities.ModelType][]uint),
+		logs:    []*OpLog{},
+		scores:  []*Score{},
+	}
+	for _, key := range entities.TypeList {
+		if key.IsDB() {
+			b.records[key] = make(map[uint]entities.Persistable)
+		}
+	}
+	return b
+}
+
+// merge appends newer changes. Newer copy overrides older one.
+func (b *backupBatch) merge(x *backupBatch) {
+	if x == nil {
+		return
+	}
+	for key, rs := range x.records {
+		for id, r := range rs {
+			b.records[key][id] = r
+		}
+	}
+	for key, ids := range x.deletes {
+		b.deletes[key] = append(b.deletes[key], ids...)
+	}
+	b.logs = append(b.logs, x.logs...)
+	b.scores = append(b.scores, x.scores...)
+	if x.clock != nil {
+		b.clock = x.clock
+	}
+}
+
+// len returns the number of records to write.
+func (b *backupBatch) len() int {
+	if b == nil {
+		return 0
+	}
+	cnt := len(b.logs) + len(b.scores)
+	if b.clock != nil {
+		cnt++
+	}
+	for key := range b.records {
+		cnt += len(b.records[key]) + len(b.deletes[key])
+	}
+	return cnt
+}
+
 // StartBackupTicker start tikcer
 func StartBackupTicker() {
 	backupTicker = time.NewTicker(conf.Game.Service.Backup.Interval.D)
@@ -34,68 +129,245 @@ func watchBackup() {
 	}
 }
 
-// Backup set model to database
-func Backup(withLock bool) {
+// Backup set model to database.
+// Changes are copied under lock and written without lock,
+// so that game and operations are not stalled by database I/O.
+// When writing fails, changes are kept as dirty and written by next backup.
+func Backup(withLock bool) error {
 	start := time.Now()
 	if withLock {
-		MuModel.RLock()
-		defer MuModel.RUnlock()
+		MuModel.Lock()
 	}
 	lock := time.Now()
+	new, up, del, skip := captureBackup()
+	if withLock {
+		MuModel.Unlock()
+	}
+	WarnLongExec(start, lock, conf.Game.Service.Perf.Backup.D, "backup")
+	log.Printf("backup was captured (new %d, up %d, del %d, skip %d)", new, up, del, skip)
 
-	tx := db.Begin()
-	new, up, del, skip := persistStatic(tx)
-	logOp := logOperation(tx)
-	tx.Commit()
+	return flushBackup()
+}
 
-	WarnLongExec(start, lock, conf.Game.Service.Perf.Backup.D, "backup")
-	log.Printf("backup was successfully ended (new %d, up %d, del %d, skip %d, log %d)", new, up, del, skip, logOp)
+// GetBackupStats returns metrics of backup
+func GetBackupStats() BackupStats {
+	muBackupQueue.Lock()
+	defer muBackupQueue.Unlock()
+	return backupStats
 }
 
-func persistStatic(tx *gorm.DB) (int, int, int, int) {
-	// upsert
-	var createCnt, updateCnt, skipCnt int
-	for _, res := range entities.TypeList {
-		if res.IsDB() {
-			Model.ForEach(res, func(raw entities.Entity) {
-				obj := raw.(entities.Persistable)
+// initBackup discards changes of previous Model.
+func initBackup() {
+	muBackupQueue.Lock()
+	defer muBackupQueue.Unlock()
+	backupQueue = nil
+	backupStats.Pending = 0
+	backupTicks = 0
+}
+
+// captureBackup copies changed entities, removed ids, operation logs, scores and Ticks to backupQueue.
+// It must be called with lock of MuModel.
+func captureBackup() (int, int, int, int) {
+	var createCnt, updateCnt, removeCnt, skipCnt int
+	b := newBackupBatch()
+	for _, key := range entities.TypeList {
+		if !key.IsDB() {
+			continue
+		}
+		Model.ForEach(key, func(raw entities.Entity) {
+			obj := raw.(entities.Persistable)
+			if obj.P().IsChanged() {
+				b.records[key][obj.B().ID] = copyEntity(raw)
 				if obj.P().IsNew() {
-					db.Create(obj)
-					obj.P().Reset()
 					createCnt++
-				} else if obj.P().IsChanged() {
-					tx.Save(obj)
-					obj.P().Reset()
-					updateCnt++
 				} else {
-					skipCnt++
+					updateCnt++
 				}
-			})
+				obj.P().Reset()
+			} else {
+				skipCnt++
+			}
+		})
+		b.deletes[key] = append(b.deletes[key], Model.Deletes[key]...)
+		removeCnt += len(Model.Deletes[key])
+		Model.Deletes[key] = Model.Deletes[key][:0]
+	}
+	// OpLog is not referred after captured
+	b.logs = OpCache
+	OpCache = []*OpLog{}
+	// Score is also referred by ranking
+	for _, s := range ScoreCache {
+		x := *s
+		b.scores = append(b.scores, &x)
+	}
+	ScoreCache = ScoreCache[:0]
+
+	muBackupQueue.Lock()
+	defer muBackupQueue.Unlock()
+	if GameClock.Ticks != backupTicks {
+		backupTicks = GameClock.Ticks
+		b.clock = &ClockState{ID: 1, Ticks: backupTicks}
+	}
+	if backupQueue == nil {
+		backupQueue = b
+	} else {
+		backupQueue.merge(b)
+	}
+	backupStats.Pending = backupQueue.len()
+	return createCnt, updateCnt, removeCnt, skipCnt
+}
+
+// copyEntity returns shallow copy of entity.
+// Writer reads only persisted fields of it, which are values.
+func copyEntity(obj entities.Entity) entities.Persistable {
+	v := reflect.ValueOf(obj).Elem()
+	c := reflect.New(v.Type())
+	c.Elem().Set(v)
+	return c.Interface().(entities.Persistable)
+}
+
+// flushBackup writes backupQueue to database with retry.
+// Changes are kept for next backup when all retries fail.
+func flushBackup() error {
+	muBackupWriter.Lock()
+	defer muBackupWriter.Unlock()
+
+	muBackupQueue.Lock()
+	b := backupQueue
+	backupQueue = nil
+	muBackupQueue.Unlock()
+	if b.len() == 0 {
+		return nil
+	}
+
+	start := time.Now()
+	var err error
+	for i := 0; i <= conf.Game.Service.Backup.Retry; i++ {
+		if i > 0 {
+			log.Printf("failed to write backup (%v). retry after %v.", err, conf.Game.Service.Backup.Wait.D)
+			time.Sleep(conf.Game.Service.Backup.Wait.D)
+			muBackupQueue.Lock()
+			backupStats.Retried++
+			muBackupQueue.Unlock()
+		}
+		if err = writeBackup(b); err == nil {
+			break
 		}
 	}
 
-	// remove old resources
-	removeCnt := 0
-	for i := len(entities.TypeList) - 1; i >= 0; i-- {
-		key := entities.TypeList[i]
-		if key.IsDB() {
-			for _, id := range Model.Deletes[key] {
-				sql := fmt.Sprintf("UPDATE %s SET updated_at = ?, deleted_at = ? WHERE id = ?", key.Table())
-				tx.Exec(sql, time.Now(), time.Now(), id)
-				removeCnt++
-			}
-			Model.Deletes[key] = Model.Deletes[key][:0]
+	muBackupQueue.Lock()
+	defer muBackupQueue.Unlock()
+	if err != nil {
+		// changes captured while writing are newer
+		b.merge(backupQueue)
+		backupQueue = b
+		backupStats.Failed++
+		backupStats.LastError = err.Error()
+		log.Printf("failed to write backup (%v). %d records are kept for next backup.", err, b.len())
+	} else {
+		backupStats.Succeeded++
+		backupStats.LastSuccess = time.Now()
+		backupStats.LastError = ""
+		log.Printf("backup was successfully written %d records in %.2f sec", b.len(), time.Since(start).Seconds())
+	}
+	backupStats.Pending = backupQueue.len()
+	return err
+}
+
+// writeBackup writes changes in one transaction.
+func writeBackup(b *backupBatch) error {
+	tx, err := store.Begin()
+	if err != nil {
+		return err
+	}
+	if err := persistStatic(tx, b); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := logOperation(tx, b); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := logScore(tx, b); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := persistClock(tx, b); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// persistStatic upserts changed entities per table and marks removed ones as deleted.
+// Referred tables are upserted first and referring tables are deleted first.
+func persistStatic(tx storage.Tx, b *backupBatch) error {
+	now := time.Now()
+	order := persistOrder()
+	for _, key := range order {
+		var columns []string
+		rows := [][]interface{}{}
+		for _, id := range recordIDs(b.records[key]) {
+			obj := b.records[key][id]
+			obj.P().UpdatedAt = now
+			var row []interface{}
+			columns, row = store.Columns(obj)
+			rows = append(rows, row)
+		}
+		if err := tx.Upsert(key.Table(), "id", columns, rows); err != nil {
+			return err
 		}
 	}
-	return createCnt, updateCnt, removeCnt, skipCnt
+
+	for i := len(order) - 1; i >= 0; i-- {
+		key := order[i]
+		if err := tx.SoftDelete(key.Table(), b.deletes[key], now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func logOperation(tx storage.Tx, b *backupBatch) error {
+	return insertLogs(tx, "op_logs", len(b.logs), func(i int) interface{} {
+		return b.logs[i]
+	})
+}
+
+func logScore(tx storage.Tx, b *backupBatch) error {
+	return insertLogs(tx, "scores", len(b.scores), func(i int) interface{} {
+		return b.scores[i]
+	})
+}
+
+// persistClock overwrites progress of GameClock.
+func persistClock(tx storage.Tx, b *backupBatch) error {
+	if b.clock == nil {
+		return nil
+	}
+	columns, row := store.Columns(b.clock)
+	return tx.Upsert("clock_states", "id", columns, [][]interface{}{row})
+}
+
+// insertLogs inserts records having auto increment id in bulk.
+func insertLogs(tx storage.Tx, table string, n int, get func(int) interface{}) error {
+	var columns []string
+	rows := [][]interface{}{}
+	for i := 0; i < n; i++ {
+		obj := get(i)
+		var row []interface{}
+		columns, row = store.Columns(obj, "id")
+		rows = append(rows, row)
+	}
+	return tx.Insert(table, columns, rows)
 }
 
-func logOperation(tx *gorm.DB) int {
-	logCnt := 0
-	for _, op := range OpCache {
-		tx.Create(op)
-		logCnt++
+// recordIDs returns ids of records in ascending order.
+func recordIDs(records map[uint]entities.Persistable) []uint {
+	ids := make([]uint, 0, len(records))
+	for id := range records {
+		ids = append(ids, id)
 	}
-	OpCache = OpCache[:0]
-	return logCnt
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
 }
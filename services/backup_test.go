@@ -0,0 +1,229 @@
+package services
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yasshi2525/RushHour/auth"
+	"github.com/yasshi2525/RushHour/config"
+	"github.com/yasshi2525/RushHour/entities"
+)
+
+func TestBackup(t *testing.T) {
+	wd, _ := os.Getwd()
+	conf, _ = config.Load(fmt.Sprintf("%s/../config", wd))
+	conf.Game.Service.Routing.Worker = 1
+	conf.Game.Economy.Initial = 1000000
+	auther, _ = auth.GetAuther(conf.Secret.Auth)
+
+	InitLock()
+	InitRepository()
+	defer openTestDB(t)()
+
+	MuModel.Lock()
+	admin, _ := CreatePlayer("admin", "admin", "admin", 0, entities.Admin)
+	o, _ := CreatePlayer("test", "test", "test", 0, entities.Normal)
+	CreateRailNode(o, 0, 0, 0)
+	var rn *entities.RailNode
+	for _, x := range Model.RailNodes {
+		rn = x
+	}
+	ExtendRailNode(o, rn, 10, 0, 0)
+	st, _ := CreateStation(o, rn, "st")
+	l, _ := CreateRailLine(o, "l", false, false)
+	StartRailLine(o, l, st.Platform)
+	for _, re := range rn.OutEdges {
+		InsertLineTaskRailEdge(o, l, re)
+	}
+	RingRailLine(o, l)
+	tr, _ := CreateTrain(o, "tr")
+	if err := DeployTrain(o, tr, l); err != nil {
+		t.Fatalf("DeployTrain() got %v", err)
+	}
+	GameClock.Ticks = 42
+	MuModel.Unlock()
+	waitRefresh(t)
+	Backup(true)
+
+	InitRepository()
+	Restore(true)
+	if got := GameClock.Ticks; got != 42 {
+		t.Errorf("Ticks got %d, want 42", got)
+	}
+	if got := len(Model.Players); got != 2 {
+		t.Errorf("len(Players) got %d, want 2", got)
+	}
+	if got := len(Model.RailNodes); got != 2 {
+		t.Errorf("len(RailNodes) got %d, want 2", got)
+	}
+	if got := len(Model.RailEdges); got != 2 {
+		t.Errorf("len(RailEdges) got %d, want 2", got)
+	}
+	if x, ok := Model.Players[o.ID]; !ok || x.LoginID != o.LoginID {
+		t.Errorf("Players[%d] got %v, want %v", o.ID, x, o)
+	}
+	// Train is deployed over Chunk after its LineTask is resolved
+	if x := Model.Trains[tr.ID]; x == nil || x.Task() == nil {
+		t.Errorf("Trains[%d] got %v, want deployed", tr.ID, x)
+	} else if dt, err := FindDelegateTrain(x, conf.Game.Entity.MaxScale); err != nil || dt == nil {
+		t.Errorf("FindDelegateTrain() got (%v, %v), want restored Train over Chunk", dt, err)
+	}
+
+	// purge is rolled back when any table fails
+	residences := schemaAt(LatestSchema())[entities.RESIDENCE.Table()]
+	store.DropTable(residences.Name)
+	if err := PurgeDB(nil); err == nil {
+		t.Errorf("PurgeDB() without %s got nil, want error", residences.Name)
+	}
+	if got, _ := store.Max(entities.RAILNODE.Table(), "id"); got == 0 {
+		t.Errorf("%s got empty, want kept after failed purge", entities.RAILNODE.Table())
+	}
+	store.CreateTable(residences)
+
+	if err := PurgeDB(admin); err != nil {
+		t.Fatalf("PurgeDB() got %v, want nil", err)
+	}
+	InitRepository()
+	Restore(true)
+	if got := len(Model.Players); got != 1 {
+		t.Errorf("len(Players) got %d, want 1 after purge", got)
+	}
+	if got := len(Model.RailNodes); got != 0 {
+		t.Errorf("len(RailNodes) got %d, want 0 after purge", got)
+	}
+}
+
+func TestBackupRetry(t *testing.T) {
+	wd, _ := os.Getwd()
+	conf, _ = config.Load(fmt.Sprintf("%s/../config", wd))
+	conf.Game.Service.Routing.Worker = 1
+	conf.Game.Service.Backup.Retry = 1
+	conf.Game.Service.Backup.Wait.D = 0
+	auther, _ = auth.GetAuther(conf.Secret.Auth)
+
+	InitLock()
+	InitRepository()
+	defer openTestDB(t)()
+	before := GetBackupStats()
+
+	MuModel.Lock()
+	o, _ := CreatePlayer("test", "test", "test", 0, entities.Normal)
+	MuModel.Unlock()
+
+	store.DropTable(opLogTable.Name)
+	Backup(true)
+	stats := GetBackupStats()
+	if got, want := stats.Failed, before.Failed+1; got != want {
+		t.Errorf("Failed got %d, want %d", got, want)
+	}
+	if got, want := stats.Retried, before.Retried+1; got != want {
+		t.Errorf("Retried got %d, want %d", got, want)
+	}
+	if stats.Pending == 0 || stats.LastError == "" {
+		t.Errorf("stats got %+v, want pending records and error", stats)
+	}
+
+	store.CreateTable(opLogTable)
+	Backup(true)
+	stats = GetBackupStats()
+	if got, want := stats.Succeeded, before.Succeeded+1; got != want {
+		t.Errorf("Succeeded got %d, want %d", got, want)
+	}
+	if stats.Pending != 0 || stats.LastError != "" {
+		t.Errorf("stats got %+v, want no pending records and no error", stats)
+	}
+
+	InitRepository()
+	Restore(true)
+	if x, ok := Model.Players[o.ID]; !ok || x.LoginID != o.LoginID {
+		t.Errorf("Players[%d] got %v, want %v", o.ID, x, o)
+	}
+}
+
+func TestBackupBatch(t *testing.T) {
+	wd, _ := os.Getwd()
+	conf, _ = config.Load(fmt.Sprintf("%s/../config", wd))
+	conf.Game.Service.Routing.Worker = 1
+	auther, _ = auth.GetAuther(conf.Secret.Auth)
+
+	InitLock()
+	InitRepository()
+	defer openTestDB(t)()
+
+	MuModel.Lock()
+	admin, _ := CreatePlayer("admin", "admin", "admin", 0, entities.Admin)
+	// more rows than placeholders SQLite accepts in a statement
+	for i := 0; i < 200; i++ {
+		CreateResidence(admin, float64(i), 0)
+	}
+	MuModel.Unlock()
+	if err := Backup(true); err != nil {
+		t.Fatalf("Backup() got %v, want nil", err)
+	}
+
+	MuModel.Lock()
+	admin.Money = 12345
+	admin.Change()
+	var removed uint
+	for id := range Model.Residences {
+		removed = id
+		break
+	}
+	RemoveResidence(admin, removed)
+	MuModel.Unlock()
+	if err := Backup(true); err != nil {
+		t.Fatalf("Backup() got %v, want nil", err)
+	}
+
+	InitRepository()
+	Restore(true)
+	if got := len(Model.Residences); got != 199 {
+		t.Errorf("len(Residences) got %d, want 199", got)
+	}
+	if _, ok := Model.Residences[removed]; ok {
+		t.Errorf("Residences[%d] got restored, want removed", removed)
+	}
+	if got := Model.Players[admin.ID].Money; got != 12345 {
+		t.Errorf("Money got %d, want 12345", got)
+	}
+}
+
+func TestPersistOrder(t *testing.T) {
+	entities.InitType()
+	pos := make(map[entities.ModelType]int)
+	for i, key := range persistOrder() {
+		pos[key] = i
+	}
+	tables := schemaAt(LatestSchema())
+	for from := range pos {
+		for _, fk := range tables[from.Table()].ForeignKeys {
+			for to := range pos {
+				if to.Table() == fk.Table && pos[to] >= pos[from] {
+					t.Errorf("%v got after %v, want before (%s)", to, from, fk.Column)
+				}
+			}
+		}
+	}
+}
+
+// openTestDB creates database on temporary file and returns function to remove it.
+func openTestDB(t *testing.T) func() {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "rushhour")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf.Secret.DB.Driver = "sqlite3"
+	conf.Secret.DB.Spec = filepath.Join(dir, "rushhour.db")
+	store = connectDB()
+	if err := MigrateDB(); err != nil {
+		t.Fatal(err)
+	}
+	return func() {
+		closeDB()
+		os.RemoveAll(dir)
+	}
+}
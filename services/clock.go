@@ -0,0 +1,149 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/yasshi2525/RushHour/entities"
+)
+
+// Clock advances simulation by fixed steps.
+// Wall clock only decides how many steps are run in a procedure,
+// so that the same sequence of steps always produces the same result.
+type Clock struct {
+	// Ticks is the number of steps since game began
+	Ticks uint64
+	// Multiplier is ratio of simulated time to real time. 0 means pause.
+	Multiplier float64
+	// pending is simulated time not consumed by steps yet
+	pending time.Duration
+}
+
+// ClockState is progress of GameClock persisted in database.
+// Only one record whose ID is 1 exists.
+type ClockState struct {
+	ID    uint
+	Ticks uint64
+}
+
+// ClockStatus represents simulated time for client
+type ClockStatus struct {
+	Ticks      uint64  `json:"ticks"`
+	Elapsed    float64 `json:"elapsed"`
+	Multiplier float64 `json:"multiplier"`
+	Day        int     `json:"day"`
+	// TimeOfDay is seconds from in-game midnight
+	TimeOfDay float64 `json:"time_of_day"`
+	// Clock is in-game time of day formatted as hh:mm
+	Clock string `json:"clock"`
+}
+
+// GameClock is simulated time of game
+var GameClock *Clock
+
+func newClock() *Clock {
+	return &Clock{Multiplier: conf.Game.Service.Clock.Multiplier}
+}
+
+// stepDuration returns simulated time of one step.
+// It is procedure interval unless it is configured.
+func stepDuration() time.Duration {
+	if step := conf.Game.Service.Clock.Step.D; step > 0 {
+		return step
+	}
+	return conf.Game.Service.Procedure.Interval.D
+}
+
+// dayDuration returns simulated time of one in-game day.
+func dayDuration() time.Duration {
+	if day := conf.Game.Service.Clock.Day.D; day > 0 {
+		return day
+	}
+	return 24 * time.Hour
+}
+
+// gameEpoch is when game began in terms of Clock.Now.
+var gameEpoch = time.Unix(0, 0).UTC()
+
+// Now returns simulated time as timestamp, which is elapsed time since gameEpoch.
+// It stops while game is paused, unlike wall clock.
+func (c *Clock) Now() time.Time {
+	return gameEpoch.Add(c.Elapsed())
+}
+
+// Elapsed returns simulated time since game began.
+func (c *Clock) Elapsed() time.Duration {
+	return time.Duration(c.Ticks) * stepDuration()
+}
+
+// Day returns the number of in-game days passed since game began.
+func (c *Clock) Day() int {
+	return int((conf.Game.Service.Clock.Start.D + c.Elapsed()) / dayDuration())
+}
+
+// TimeOfDay returns in-game time from midnight, which is scaled to 24 hours.
+func (c *Clock) TimeOfDay() time.Duration {
+	day := dayDuration()
+	sim := (conf.Game.Service.Clock.Start.D + c.Elapsed()) % day
+	return time.Duration(float64(sim) / float64(day) * float64(24*time.Hour))
+}
+
+// Status returns simulated time for client.
+func (c *Clock) Status() *ClockStatus {
+	tod := c.TimeOfDay()
+	return &ClockStatus{
+		Ticks:      c.Ticks,
+		Elapsed:    c.Elapsed().Seconds(),
+		Multiplier: c.Multiplier,
+		Day:        c.Day(),
+		TimeOfDay:  tod.Seconds(),
+		Clock:      fmt.Sprintf("%02d:%02d", int(tod.Hours()), int(tod.Minutes())%60),
+	}
+}
+
+// advance accumulates elapsed real time and returns the number of steps to run.
+// Simulated time lagging more than a few procedures is dropped in order not to stall the game.
+func (c *Clock) advance(real time.Duration) int {
+	c.pending += time.Duration(float64(real) * c.Multiplier)
+	if max := time.Duration(4 * float64(conf.Game.Service.Procedure.Interval.D) * c.Multiplier); c.pending > max {
+		log.Printf("simulation dropped %v because procedure is too slow", c.pending-max)
+		c.pending = max
+	}
+	step := stepDuration()
+	n := int(c.pending / step)
+	c.pending -= time.Duration(n) * step
+	return n
+}
+
+// SetMultiplier changes speed of simulation. 0 means pause.
+func SetMultiplier(v float64) error {
+	if v < 0 {
+		return fmt.Errorf("multiplier must be gte 0: %f", v)
+	}
+	GameClock.Multiplier = v
+	if v == 0 {
+		GameClock.pending = 0
+	}
+	return nil
+}
+
+// StepGame proceeds simulation by specified number of steps regardless of wall clock.
+func StepGame(n int) {
+	for i := 0; i < n; i++ {
+		stepGame()
+	}
+}
+
+// stepGame proceeds simulation by one step.
+// Route calculated in background is applied at the beginning of step.
+func stepGame() {
+	applyRouting()
+	sec := stepDuration().Seconds()
+	Model.ForEachSorted(entities.TRAIN, func(obj entities.Entity) {
+		obj.(*entities.Train).Step(sec)
+	})
+	processResidence(sec)
+	processHuman(sec)
+	GameClock.Ticks++
+}
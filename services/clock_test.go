@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/yasshi2525/RushHour/auth"
+	"github.com/yasshi2525/RushHour/config"
+)
+
+func TestClock(t *testing.T) {
+	wd, _ := os.Getwd()
+	conf, _ = config.Load(fmt.Sprintf("%s/../config", wd))
+	conf.Game.Service.Routing.Worker = 1
+	conf.Game.Entity.MaxScale = 2
+	conf.Game.Service.Procedure.Interval.D = time.Second
+	conf.Game.Service.Clock.Step.D = 100 * time.Millisecond
+	conf.Game.Service.Clock.Multiplier = 1
+	conf.Game.Service.Clock.Day.D = 24 * time.Minute
+	conf.Game.Service.Clock.Start.D = 6 * time.Minute
+	auther, _ = auth.GetAuther(conf.Secret.Auth)
+
+	InitRepository()
+
+	t.Run("advance", func(t *testing.T) {
+		GameClock = newClock()
+		if n := GameClock.advance(250 * time.Millisecond); n != 2 {
+			t.Errorf("advance(250ms) got %d steps, want 2", n)
+		}
+		// remaining 50ms is carried over
+		if n := GameClock.advance(50 * time.Millisecond); n != 1 {
+			t.Errorf("advance(50ms) got %d steps, want 1", n)
+		}
+		// lag over 4 procedures is dropped
+		if n := GameClock.advance(time.Minute); n != 40 {
+			t.Errorf("advance(1m) got %d steps, want 40", n)
+		}
+	})
+
+	t.Run("multiplier", func(t *testing.T) {
+		GameClock = newClock()
+		SetMultiplier(4)
+		if n := GameClock.advance(time.Second); n != 40 {
+			t.Errorf("4x advance(1s) got %d steps, want 40", n)
+		}
+		SetMultiplier(0)
+		if n := GameClock.advance(time.Second); n != 0 {
+			t.Errorf("paused advance(1s) got %d steps, want 0", n)
+		}
+		if err := SetMultiplier(-1); err == nil {
+			t.Errorf("SetMultiplier(-1) got nil, want error")
+		}
+	})
+
+	t.Run("time of day", func(t *testing.T) {
+		GameClock = newClock()
+		if got := GameClock.Status().Clock; got != "06:00" {
+			t.Errorf("initial clock got %s, want 06:00", got)
+		}
+		// 1 simulated minute = 1 in-game hour
+		StepGame(600)
+		if got := GameClock.Status(); got.Clock != "07:00" || got.Ticks != 600 || got.Elapsed != 60 {
+			t.Errorf("clock after 1m got %+v, want 07:00", got)
+		}
+		GameClock.Ticks += 17 * 600
+		if got := GameClock.Status(); got.Clock != "00:00" || got.Day != 1 {
+			t.Errorf("clock after 18m got %+v, want day 1 00:00", got)
+		}
+	})
+	t.Run("routing", func(t *testing.T) {
+		InitRepository()
+		processRouting(context.Background())
+		if RouteTemplate != nil || !IsSearching() {
+			t.Errorf("route got applied before tick boundary, want pending")
+		}
+		StepGame(1)
+		if RouteTemplate == nil || IsSearching() {
+			t.Errorf("route got pending after step, want applied")
+		}
+	})
+}
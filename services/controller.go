@@ -2,16 +2,57 @@ package services
 
 import (
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/yasshi2525/RushHour/entities"
 )
 
-// ViewDelegateMap returns delegate Entity for client view.
-func ViewDelegateMap(x int, y int, scale int, delegate int) *entities.DelegateMap {
-	dm := &entities.DelegateMap{}
-	dm.Init()
-	Model.RootCluster.ViewMap(dm, &entities.ChunkPoint{X: x, Y: y, Scale: scale}, delegate)
-	return dm
+// ViewDelegateMap returns whole delegates in viewport of published View.
+func ViewDelegateMap(x int, y int, scale int, delegate int) *entities.MapData {
+	return CurrentView().Map.ViewMap(&entities.ChunkPoint{X: x, Y: y, Scale: scale}, delegate, time.Time{})
+}
+
+// ViewDelegateMapSince returns delegates changed after since (unix time) and id of removed ones in published View.
+// It returns whole map when removal after since has been already pruned.
+func ViewDelegateMapSince(x int, y int, scale int, delegate int, since int64) *entities.MapData {
+	return CurrentView().Map.ViewMap(&entities.ChunkPoint{X: x, Y: y, Scale: scale}, delegate, time.Unix(since, 0))
+}
+
+// FindDelegatePlatform returns delegate of Platform in specified scale.
+func FindDelegatePlatform(p *entities.Platform, scale int) (*entities.DelegatePlatform, error) {
+	if ch := Model.RootCluster.FindChunk(p, scale); ch != nil {
+		return ch.Platform, nil
+	}
+	return nil, fmt.Errorf("invalid scale=%d", scale)
+}
+
+// FindDelegateTrain returns delegate of deployed Train in specified scale.
+func FindDelegateTrain(t *entities.Train, scale int) (*entities.DelegateTrain, error) {
+	if ch := Model.RootCluster.FindChunk(t, scale); ch != nil {
+		return ch.Train, nil
+	}
+	return nil, fmt.Errorf("invalid scale=%d or undeployed %v", scale, t)
+}
+
+// FindDelegateLineTasks returns delegates of LineTasks of RailLine in specified scale ordered by id.
+func FindDelegateLineTasks(l *entities.RailLine, scale int) ([]*entities.DelegateLineTask, error) {
+	found := make(map[uint]*entities.DelegateLineTask)
+	for _, lt := range l.Tasks {
+		fch := Model.RootCluster.FindChunk(lt, scale)
+		tch := Model.RootCluster.FindChunk(lt.To(), scale)
+		if fch == nil || tch == nil {
+			return nil, fmt.Errorf("invalid scale=%d", scale)
+		}
+		dlt := fch.OutLineTasks[tch.ID]
+		found[dlt.ID] = dlt
+	}
+	list := []*entities.DelegateLineTask{}
+	for _, dlt := range found {
+		list = append(list, dlt)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list, nil
 }
 
 // CheckAuth throws error when there is no permission
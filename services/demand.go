@@ -0,0 +1,19 @@
+package services
+
+import (
+	"math"
+	"time"
+)
+
+// demandRate returns multiplier of demand at specified time of day.
+// Hourly profile is interpolated linearly and empty profile means flat demand.
+func demandRate(profile []float64, tod time.Duration) float64 {
+	if len(profile) == 0 {
+		return 1
+	}
+	h := tod.Hours() * float64(len(profile)) / 24
+	i := int(h) % len(profile)
+	j := (i + 1) % len(profile)
+	frac := h - math.Floor(h)
+	return profile[i]*(1-frac) + profile[j]*frac
+}
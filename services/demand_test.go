@@ -0,0 +1,93 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/yasshi2525/RushHour/auth"
+	"github.com/yasshi2525/RushHour/config"
+	"github.com/yasshi2525/RushHour/entities"
+)
+
+func TestDemandRate(t *testing.T) {
+	profile := make([]float64, 24)
+	profile[7], profile[8], profile[23] = 2, 4, 1
+
+	cases := []struct {
+		profile []float64
+		in      time.Duration
+		want    float64
+	}{
+		{nil, 8 * time.Hour, 1},
+		{profile, 7 * time.Hour, 2},
+		{profile, 7*time.Hour + 30*time.Minute, 3},
+		{profile, 12 * time.Hour, 0},
+		// wraps around midnight
+		{profile, 23*time.Hour + 30*time.Minute, 0.5},
+	}
+	for _, c := range cases {
+		if got := demandRate(c.profile, c.in); got != c.want {
+			t.Errorf("demandRate(%v, %v) got %f, want %f", c.profile, c.in, got, c.want)
+		}
+	}
+}
+
+func TestOutboundDemand(t *testing.T) {
+	wd, _ := os.Getwd()
+	conf, _ = config.Load(fmt.Sprintf("%s/../config", wd))
+	conf.Game.Service.Routing.Worker = 1
+	conf.Game.Entity.Human.Work.D = time.Minute
+	conf.Game.Entity.Demand.Outbound = make([]float64, 24)
+	conf.Game.Entity.Demand.Outbound[18] = 2
+	auther, _ = auth.GetAuther(conf.Secret.Auth)
+
+	for _, c := range []struct {
+		clock time.Duration
+		want  entities.Standing
+	}{
+		// no one leaves before evening
+		{10 * time.Minute, entities.OnCompany},
+		// work progresses twice as fast at 18:00
+		{18 * time.Minute, entities.OnGround},
+	} {
+		conf.Game.Service.Clock.Start.D = c.clock
+		InitRepository()
+		r := Model.NewResidence(0, 0)
+		cp := Model.NewCompany(10, 0)
+		h := Model.NewHuman(r, cp)
+		h.Arrive(cp)
+
+		processHuman(30)
+		if h.On != c.want {
+			t.Errorf("Human at %s got %v, want %v", GameClock.Status().Clock, h.On, c.want)
+		}
+	}
+}
+
+func TestChangeDemand(t *testing.T) {
+	wd, _ := os.Getwd()
+	conf, _ = config.Load(fmt.Sprintf("%s/../config", wd))
+	conf.Game.Service.Routing.Worker = 1
+	auther, _ = auth.GetAuther(conf.Secret.Auth)
+	InitRepository()
+
+	admin, _ := CreatePlayer("admin", "admin", "admin", 0, entities.Admin)
+	o, _ := CreatePlayer("test", "test", "test", 0, entities.Normal)
+	r, _ := CreateResidence(admin, 0, 0)
+	c, _ := CreateCompany(admin, 10, 0)
+
+	if err := ChangeResidenceDemand(o, r, 2); err == nil {
+		t.Errorf("ChangeResidenceDemand() by normal player got nil, want error")
+	}
+	if err := ChangeCompanyDemand(admin, c, -1); err == nil {
+		t.Errorf("ChangeCompanyDemand(-1) got nil, want error")
+	}
+	if err := ChangeResidenceDemand(admin, r, 2); err != nil || r.Demand != 2 {
+		t.Errorf("ChangeResidenceDemand(2) got (%v, %f), want (nil, 2)", err, r.Demand)
+	}
+	if err := ChangeCompanyDemand(admin, c, 0); err != nil || c.Demand != 0 {
+		t.Errorf("ChangeCompanyDemand(0) got (%v, %f), want (nil, 0)", err, c.Demand)
+	}
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"math"
+
+	"github.com/yasshi2525/RushHour/entities"
+)
+
+// railCost returns construction cost of RailEdge between two points (both directions).
+func railCost(from *entities.Point, to *entities.Point) int64 {
+	if conf.Game.Economy.Rail == 0 {
+		// avoid NaN when Train speed is not configured either
+		return 0
+	}
+	return int64(math.Ceil(2 * Model.RailCost(from, to) * conf.Game.Economy.Rail))
+}
+
+// initMoney gives initial money to new Player.
+func initMoney(o *entities.Player) {
+	o.Money = conf.Game.Economy.Initial
+	o.Change()
+}
+
+// processFare credits fare which passengers paid since last procedure.
+func processFare() {
+	for _, o := range Model.Players {
+		if num, dist := o.Settle(); num > 0 {
+			o.Earn(fare(num, dist))
+		}
+	}
+}
+
+// fare returns amount passengers pay for specified number of rides and total distance.
+func fare(num int, dist float64) int64 {
+	return int64(num)*conf.Game.Economy.BaseFare + int64(dist*conf.Game.Economy.DistFare)
+}
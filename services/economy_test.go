@@ -0,0 +1,54 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/yasshi2525/RushHour/auth"
+	"github.com/yasshi2525/RushHour/config"
+	"github.com/yasshi2525/RushHour/entities"
+)
+
+func TestExtendRailNode(t *testing.T) {
+	wd, _ := os.Getwd()
+	conf, _ = config.Load(fmt.Sprintf("%s/../config", wd))
+	conf.Game.Service.Routing.Worker = 1
+	conf.Game.Entity.MaxScale = 2
+	conf.Game.Economy.Initial = 100
+	conf.Game.Economy.Rail = 100
+	auther, _ = auth.GetAuther(conf.Secret.Auth)
+
+	InitRepository()
+	isInOperation = true
+	// operations request refreshing tracks in background
+	MuModel.Lock()
+	defer waitRefresh(t)
+	defer MuModel.Unlock()
+
+	o, _ := CreatePlayer("test", "test", "test", 0, entities.Normal)
+	if got := o.Money; got != 100 {
+		t.Errorf("Money should be 100, but %d", got)
+	}
+
+	CreateRailNode(o, 0, 0, 2)
+	var from *entities.RailNode
+	for _, rn := range Model.RailNodes {
+		from = rn
+	}
+	speed := conf.Game.Entity.Train.Speed
+
+	if _, _, err := ExtendRailNode(o, from, speed, 0, 2); err == nil {
+		t.Errorf("ExtendRailNode should be failed by insufficient funds")
+	}
+	if got := len(Model.RailNodes); got != 1 {
+		t.Errorf("RailNodes size should be 1, but %d", got)
+	}
+
+	if _, _, err := ExtendRailNode(o, from, speed/4, 0, 2); err != nil {
+		t.Error(err)
+	}
+	if got := o.Money; got != 50 {
+		t.Errorf("Money should be 50, but %d", got)
+	}
+}
@@ -1 +1,101 @@
 package services
+
+import (
+	"math"
+
+	"github.com/yasshi2525/RushHour/entities"
+	"github.com/yasshi2525/RushHour/route"
+)
+
+// processResidence makes Residence generate Human and leads them to Company.
+// Generation follows inbound demand profile at current time of day.
+func processResidence(sec float64) {
+	rate := demandRate(conf.Game.Entity.Demand.Inbound, GameClock.TimeOfDay())
+	Model.ForEachSorted(entities.RESIDENCE, func(obj entities.Entity) {
+		for _, h := range obj.(*entities.Residence).Step(sec * rate) {
+			routeHuman(h)
+		}
+	})
+}
+
+// processHuman makes Human walk, get on/off, work, return home and die.
+// Work in Company progresses following outbound demand profile at current time of day.
+func processHuman(sec float64) {
+	rate := demandRate(conf.Game.Entity.Demand.Outbound, GameClock.TimeOfDay())
+	Model.ForEachSorted(entities.HUMAN, func(obj entities.Entity) {
+		h := obj.(*entities.Human)
+		if h.On == entities.OnCompany {
+			// Human leaving Company requires Step to Residence
+			if h.Labor(sec * rate); h.On != entities.OnCompany {
+				routeHuman(h)
+			}
+			return
+		}
+		if h.Current == nil {
+			routeHuman(h)
+		}
+		h.Step(sec)
+		// Human arriving at Gate or Platform requires next Step
+		if _, ok := Model.Humans[h.ID]; ok && h.Current == nil {
+			routeHuman(h)
+		}
+	})
+}
+
+// routeHuman sets Step which Human should go next by following RouteTemplate.
+// When Human waits on Platform, it also sets Transport Human should take.
+// Human walks to destination directly when no route is calculated for it.
+func routeHuman(h *entities.Human) {
+	dest := h.Dest()
+	var model *route.Model
+	if payload, ok := RouteTemplate[dest.B().Type()]; ok {
+		model = payload.Route[dest.B().Idx()]
+	}
+	// tie is broken by id to be independent of order of map
+	if model == nil {
+		var next *entities.Step
+		for _, s := range h.OutSteps() {
+			if s.ToNode == dest && (next == nil || s.ID < next.ID) {
+				next = s
+			}
+		}
+		if next != nil {
+			h.Current = next
+		}
+		return
+	}
+	// first hop is weighted in the same way as the rest of route
+	cm := costModel()
+	min := math.MaxFloat64
+	for _, s := range h.OutSteps() {
+		if v := cm.Cost(s) + distance(model, dest, s.ToNode); v < min || (v == min && s.ID < h.Current.ID) {
+			min = v
+			h.Current = s
+		}
+	}
+	if h.Current == nil {
+		return
+	}
+	if p, ok := h.Current.ToNode.(*entities.Platform); ok {
+		n, ok := model.Nodes[entities.PLATFORM][p.ID]
+		if ok && n.ViaEdge != nil && n.ViaEdge.ModelType == entities.TRANSPORT {
+			h.Ride = Model.Transports[n.ViaEdge.ID]
+		}
+	}
+}
+
+// distance returns how long it takes to goal from specified node.
+// It returns max float64 value when goal is unreachable.
+func distance(model *route.Model, goal entities.Entity, obj entities.Entity) float64 {
+	n, ok := model.Nodes[obj.B().Type()][obj.B().Idx()]
+	if !ok {
+		return math.MaxFloat64
+	}
+	if n.SameAs(goal) {
+		return 0
+	}
+	if n.Via == nil {
+		return math.MaxFloat64
+	}
+	return n.Value
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/yasshi2525/RushHour/auth"
+	"github.com/yasshi2525/RushHour/config"
+	"github.com/yasshi2525/RushHour/entities"
+)
+
+func TestRouteHuman(t *testing.T) {
+	wd, _ := os.Getwd()
+	conf, _ = config.Load(fmt.Sprintf("%s/../config", wd))
+	conf.Game.Service.Routing.Worker = 1
+	conf.Game.Service.Routing.Congestion.Crowd = 1000000
+	conf.Game.Entity.Platform.Capacity = 1
+	auther, _ = auth.GetAuther(conf.Secret.Auth)
+	InitRepository()
+
+	o, _ := CreatePlayer("test", "test", "test", 0, entities.Normal)
+	r := Model.NewResidence(0, 0)
+	c := Model.NewCompany(100, 0)
+	platform := func(x float64) *entities.Platform {
+		return Model.NewPlatform(Model.NewRailNode(o, x, 0), Model.NewGate(Model.NewStation(o)))
+	}
+	p0, p1 := platform(0), platform(100)
+	Model.NewTransport(p0, p1, nil, 10)
+	processRouting(context.Background())
+	applyRouting()
+
+	// Human fills Platform after routing, so only first hop sees the crowd
+	h := Model.NewHuman(r, c)
+	h.Enter(p0.WithGate, p0)
+	routeHuman(h)
+	if h.Current == nil || h.Current.ToNode != p0.WithGate {
+		t.Errorf("Current got %v, want Step to Gate avoiding full Platform", h.Current)
+	}
+
+	conf.Game.Service.Routing.Congestion.Crowd = 0
+	routeHuman(h)
+	if h.Current == nil || h.Current.ToNode != p0 {
+		t.Errorf("Current got %v, want Step to Platform without penalty", h.Current)
+	}
+}
@@ -0,0 +1,239 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/yasshi2525/RushHour/entities"
+	"github.com/yasshi2525/RushHour/route"
+)
+
+// JourneyKey represents criterion which journeys are sorted by
+const (
+	JourneyByTime      = "time"
+	JourneyByTransfers = "transfers"
+	JourneyByFare      = "fare"
+)
+
+// Leg actions
+const (
+	LegWalk  = "walk"
+	LegEnter = "enter"
+	LegExit  = "exit"
+	LegRide  = "ride"
+)
+
+// Journey is a route of Human from origin to destination
+type Journey struct {
+	Legs []*Leg `json:"legs"`
+	// Time is total seconds to destination
+	Time float64 `json:"time"`
+	// Transfers is the number of changing RailLine
+	Transfers int `json:"transfers"`
+	// Fare is total amount Human pays to Players
+	Fare int64 `json:"fare"`
+}
+
+// Leg is a part of Journey, which is walking or riding on one RailLine
+type Leg struct {
+	// Action is one of walk, enter, exit and ride
+	Action string `json:"action"`
+	From   *Spot  `json:"from"`
+	To     *Spot  `json:"to"`
+	// Time is seconds to go through this Leg
+	Time float64 `json:"time"`
+	// RailLine is id of RailLine to ride on
+	RailLine uint `json:"lid,omitempty"`
+	// LineName is name of RailLine to ride on
+	LineName string `json:"line,omitempty"`
+	// Fare is amount Human pays for riding
+	Fare int64 `json:"fare,omitempty"`
+}
+
+// Spot is Relayable which Leg starts from or ends to
+type Spot struct {
+	Type string  `json:"type"`
+	ID   uint    `json:"id"`
+	X    float64 `json:"x"`
+	Y    float64 `json:"y"`
+}
+
+// muJourney is mutex lock of route for planning journeys without lock of MuModel.
+// Route and journeys are changed with it after lock of MuModel,
+// so that holders of MuModel refer to route without it.
+var muJourney sync.RWMutex
+
+// journeyIndex is Spots of Relayables and Legs of Steps and Transports which journeys are built from.
+// It is copied from Model when route is updated and it is not changed after built.
+type journeyIndex struct {
+	spots map[entities.ModelType]map[uint]*Spot
+	legs  map[entities.ModelType]map[uint]*Leg
+}
+
+// journeys is journeyIndex for current route. It is nil until route is applied.
+var journeys *journeyIndex
+
+// initJourney discards route of previous Model.
+func initJourney() {
+	muJourney.Lock()
+	defer muJourney.Unlock()
+
+	RouteTemplate = nil
+	routeGraph = nil
+	journeys = nil
+}
+
+// indexJourney rebuilds journeys from current Model.
+// It must be called with lock of MuModel and muJourney.
+func indexJourney() {
+	idx := &journeyIndex{
+		spots: make(map[entities.ModelType]map[uint]*Spot),
+		legs: map[entities.ModelType]map[uint]*Leg{
+			entities.STEP:      make(map[uint]*Leg),
+			entities.TRANSPORT: make(map[uint]*Leg),
+		},
+	}
+	for _, t := range entities.TypeList {
+		if !t.IsRelayable() {
+			continue
+		}
+		idx.spots[t] = make(map[uint]*Spot)
+		Model.ForEach(t, func(obj entities.Entity) {
+			idx.spots[t][obj.B().Idx()] = newSpot(obj.(entities.Relayable))
+		})
+	}
+	spot := func(obj entities.Relayable) *Spot {
+		return idx.spots[obj.B().Type()][obj.B().Idx()]
+	}
+	for id, x := range Model.Transports {
+		lid, name := transportLine(x)
+		idx.legs[entities.TRANSPORT][id] = &Leg{Action: LegRide,
+			From: spot(x.FromPlatform), To: spot(x.ToPlatform), Time: x.Cost(), RailLine: lid, LineName: name}
+	}
+	for id, s := range Model.Steps {
+		leg := &Leg{Action: LegWalk, From: spot(s.FromNode), To: spot(s.ToNode), Time: s.Cost()}
+		if _, ok := s.ToNode.(*entities.Platform); ok {
+			leg.Action = LegEnter
+		} else if _, ok := s.FromNode.(*entities.Platform); ok {
+			leg.Action = LegExit
+		}
+		idx.legs[entities.STEP][id] = leg
+	}
+	journeys = idx
+}
+
+// FindJourneys returns the best journey from Residence to Company on current route
+// with at most k alternatives in ascending order of specified criterion.
+// It refers route with lock of muJourney instead of MuModel, so it isn't blocked by game procedure.
+func FindJourneys(from uint, to uint, k int, sortKey string) ([]*Journey, error) {
+	muJourney.RLock()
+	defer muJourney.RUnlock()
+
+	if journeys == nil {
+		return nil, fmt.Errorf("route is not searched yet")
+	}
+	if _, ok := journeys.spots[entities.RESIDENCE][from]; !ok {
+		return nil, fmt.Errorf("%s[%d] doesn't exist", entities.RESIDENCE, from)
+	}
+	if _, ok := journeys.spots[entities.COMPANY][to]; !ok {
+		return nil, fmt.Errorf("%s[%d] doesn't exist", entities.COMPANY, to)
+	}
+	origin := routeGraph.Nodes[entities.RESIDENCE][from]
+	goal := routeGraph.Nodes[entities.COMPANY][to]
+	if origin == nil || goal == nil {
+		return nil, fmt.Errorf("route between %s(%d) and %s(%d) is not searched yet",
+			entities.RESIDENCE, from, entities.COMPANY, to)
+	}
+
+	var model *route.Model
+	if payload, ok := RouteTemplate[entities.COMPANY]; ok {
+		model = payload.Route[to]
+	}
+	paths := route.Paths(origin, goal, k+1, route.PathTo(routeGraph, model, origin))
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no route from %s(%d) to %s(%d)",
+			entities.RESIDENCE, from, entities.COMPANY, to)
+	}
+
+	js := []*Journey{}
+	for _, p := range paths {
+		j, err := journeys.newJourney(p)
+		if err != nil {
+			return nil, err
+		}
+		js = append(js, j)
+	}
+	sort.SliceStable(js, func(i, j int) bool {
+		switch sortKey {
+		case JourneyByTransfers:
+			return js[i].Transfers < js[j].Transfers
+		case JourneyByFare:
+			return js[i].Fare < js[j].Fare
+		}
+		return js[i].Time < js[j].Time
+	})
+	return js, nil
+}
+
+// newJourney converts Path to Journey.
+// Successive Transports on the same RailLine are regarded as one ride.
+// It returns error when Path refers to removed Transport or Step,
+// which happens while route is being searched again.
+func (idx *journeyIndex) newJourney(p *route.Path) (*Journey, error) {
+	j := &Journey{Legs: []*Leg{}}
+	var rides int
+	var ride *Leg
+	for _, e := range p.Edges {
+		tmpl, ok := idx.legs[e.ModelType][e.ID]
+		if !ok {
+			return nil, fmt.Errorf("route is outdated: %s(%d) was removed", e.ModelType, e.ID)
+		}
+		if e.ModelType == entities.TRANSPORT {
+			if ride == nil || ride.RailLine != tmpl.RailLine {
+				ride = &Leg{Action: LegRide, From: tmpl.From, RailLine: tmpl.RailLine, LineName: tmpl.LineName}
+				j.Legs = append(j.Legs, ride)
+				rides++
+			}
+			ride.To = tmpl.To
+			ride.Time += tmpl.Time
+			continue
+		}
+		if ride != nil {
+			ride.Fare = fare(1, spotDist(ride.From, ride.To))
+			j.Fare += ride.Fare
+			ride = nil
+		}
+		leg := *tmpl
+		j.Legs = append(j.Legs, &leg)
+	}
+	for _, l := range j.Legs {
+		j.Time += l.Time
+	}
+	if rides > 1 {
+		j.Transfers = rides - 1
+	}
+	return j, nil
+}
+
+// transportLine returns id and name of RailLine which Transport belongs to.
+func transportLine(x *entities.Transport) (uint, string) {
+	if x.Via == nil || x.Via.RailLine == nil {
+		return 0, ""
+	}
+	return x.Via.RailLine.ID, x.Via.RailLine.Name
+}
+
+// newSpot returns Spot of Relayable. Spot without position is located at origin.
+func newSpot(obj entities.Relayable) *Spot {
+	s := &Spot{Type: obj.B().Type().String(), ID: obj.B().Idx()}
+	if pos := obj.Pos(); pos != nil {
+		s.X, s.Y = pos.X, pos.Y
+	}
+	return s
+}
+
+// spotDist returns distance between two Spots.
+func spotDist(from *Spot, to *Spot) float64 {
+	return (&entities.Point{X: from.X, Y: from.Y}).Dist(&entities.Point{X: to.X, Y: to.Y})
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/yasshi2525/RushHour/auth"
+	"github.com/yasshi2525/RushHour/config"
+	"github.com/yasshi2525/RushHour/entities"
+)
+
+func TestFindJourneys(t *testing.T) {
+	wd, _ := os.Getwd()
+	conf, _ = config.Load(fmt.Sprintf("%s/../config", wd))
+	conf.Game.Service.Routing.Worker = 1
+	auther, _ = auth.GetAuther(conf.Secret.Auth)
+	InitRepository()
+
+	o, _ := CreatePlayer("test", "test", "test", 0, entities.Normal)
+	r := Model.NewResidence(0, 0)
+	c := Model.NewCompany(100, 0)
+	platform := func(x float64) *entities.Platform {
+		return Model.NewPlatform(Model.NewRailNode(o, x, 0), Model.NewGate(Model.NewStation(o)))
+	}
+	p0, p1 := platform(0), platform(100)
+	x := Model.NewTransport(p0, p1, nil, 10)
+
+	if _, err := FindJourneys(r.ID, c.ID, 0, JourneyByTime); err == nil {
+		t.Errorf("FindJourneys() before routing got nil, want error")
+	}
+	processRouting(context.Background())
+	applyRouting()
+
+	if _, err := FindJourneys(100000, c.ID, 0, JourneyByTime); err == nil || err.Error() != "Residence[100000] doesn't exist" {
+		t.Errorf("FindJourneys() from unknown Residence got %v, want doesn't exist", err)
+	}
+
+	js, err := FindJourneys(r.ID, c.ID, 1, JourneyByTime)
+	if err != nil {
+		t.Fatalf("FindJourneys() got %v, want nil", err)
+	}
+	if got := len(js); got != 2 {
+		t.Fatalf("len(FindJourneys()) got %d, want 2", got)
+	}
+
+	best := js[0]
+	actions := []string{}
+	for _, l := range best.Legs {
+		actions = append(actions, l.Action)
+	}
+	if got, want := fmt.Sprint(actions), fmt.Sprint([]string{LegWalk, LegEnter, LegRide, LegExit, LegWalk}); got != want {
+		t.Errorf("Legs got %s, want %s", got, want)
+	}
+	if got := best.Time; got != 10 {
+		t.Errorf("Time got %f, want 10", got)
+	}
+	if got := best.Transfers; got != 0 {
+		t.Errorf("Transfers got %d, want 0", got)
+	}
+	if got, want := best.Fare, fare(1, 100); got != want {
+		t.Errorf("Fare got %d, want %d", got, want)
+	}
+
+	// alternative is walking directly
+	if got, want := len(js[1].Legs), 1; got != want {
+		t.Errorf("len(alternative.Legs) got %d, want %d", got, want)
+	}
+	if js, _ := FindJourneys(r.ID, c.ID, 1, JourneyByFare); js[0].Fare != 0 {
+		t.Errorf("cheapest Fare got %d, want 0", js[0].Fare)
+	}
+
+	// route is stale until routing from scratch ends
+	searching = true
+	Model.Delete(x)
+	UpdateRouting()
+	if _, err := FindJourneys(r.ID, c.ID, 1, JourneyByTime); err == nil {
+		t.Errorf("FindJourneys() with removed Transport got nil, want error")
+	}
+	searching = false
+	UpdateRouting()
+	if js, err := FindJourneys(r.ID, c.ID, 0, JourneyByTime); err != nil || js[0].Time != 100 || js[0].Fare != 0 {
+		t.Errorf("FindJourneys() after update got (%v, %v), want walking", js, err)
+	}
+}
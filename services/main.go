@@ -5,14 +5,14 @@ import (
 	"log"
 	"time"
 
-	"github.com/jinzhu/gorm"
-
 	"github.com/yasshi2525/RushHour/auth"
 	"github.com/yasshi2525/RushHour/config"
 	"github.com/yasshi2525/RushHour/entities"
+	"github.com/yasshi2525/RushHour/route"
+	"github.com/yasshi2525/RushHour/storage"
 )
 
-var db *gorm.DB
+var store storage.Storage
 
 var isInOperation bool
 
@@ -29,18 +29,34 @@ func Init(c *config.Config, a *auth.Auther) {
 	start := time.Now()
 
 	InitLock()
+	route.InitPool(conf.Game.Service.Routing.Worker)
 	defer WarnLongExec(start, start, conf.Game.Service.Perf.Init.D, "initialization", true)
 	InitRepository()
 	if conf.Game.Service.Backup.Enabled {
-		db = connectDB()
-		//db.LogMode(true)
-		MigrateDB()
+		store = connectDB()
+		if err := MigrateDB(); err != nil {
+			panic(err)
+		}
 		Restore(true)
 	}
 	CreateIfAdmin()
 	StartRouting()
 }
 
+// Maintain connects database without starting game and calls fn, such as migrating schema.
+func Maintain(c *config.Config, a *auth.Auther, fn func() error) error {
+	conf, auther = c, a
+	InitLock()
+	InitRepository()
+	st, err := storage.Open(conf.Secret.DB.Driver, conf.Secret.DB.Spec)
+	if err != nil {
+		return err
+	}
+	store = st
+	defer closeDB()
+	return fn()
+}
+
 // Purge deletes all user data
 func Purge(o *entities.Player) error {
 	if IsInOperation() {
@@ -49,7 +65,9 @@ func Purge(o *entities.Player) error {
 	log.Println("start purging user data")
 	defer log.Println("end purging user data")
 	if conf.Game.Service.Backup.Enabled {
-		PurgeDB(o)
+		if err := PurgeDB(o); err != nil {
+			return err
+		}
 	}
 	InitRepository()
 	if conf.Game.Service.Backup.Enabled {
@@ -62,9 +80,10 @@ func Purge(o *entities.Player) error {
 
 // Terminate finalizes after stopping game
 func Terminate() {
-	if db != nil {
+	if store != nil {
 		closeDB()
 	}
+	route.StopPool()
 }
 
 // Start start game
@@ -82,8 +101,9 @@ func Start() {
 	}
 }
 
-// Stop stop game
-func Stop() {
+// Stop stop game.
+// It returns error when backup on stopping fails.
+func Stop() error {
 	log.Println("start stopping game procedure")
 	defer log.Println("end stopping game procedure")
 	if conf.Game.Service.Procedure.Simulation {
@@ -95,38 +115,26 @@ func Stop() {
 	StopProcedure()
 	if conf.Game.Service.Backup.Enabled {
 		StopBackupTicker()
-		Backup(false)
+		// unwritten changes are kept for next backup
+		return Backup(false)
 	}
+	return nil
 }
 
-func connectDB() *gorm.DB {
-	var (
-		database *gorm.DB
-		err      error
-	)
-	driver := conf.Secret.DB.Driver
-	spec := conf.Secret.DB.Spec
-
-	for i := 1; i <= 60; i++ {
-		database, err = gorm.Open(driver, spec)
-		if err != nil {
-			log.Printf("failed to connect database(%v). retry after 10 seconds.", err)
-			time.Sleep(10 * time.Second)
-		}
-	}
-
+func connectDB() storage.Storage {
+	st, err := storage.Open(conf.Secret.DB.Driver, conf.Secret.DB.Spec)
 	if err != nil {
 		panic(fmt.Errorf("failed to connect database: %v", err))
 	}
-
 	log.Println("connect database successfully")
-	return database
+	return st
 }
 
 func closeDB() {
-	if err := db.Close(); err != nil {
+	if err := store.Close(); err != nil {
 		panic(err)
 	}
+	store = nil
 	log.Println("disconnect database successfully")
 }
 
@@ -2,68 +2,297 @@ package services
 
 import (
 	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/yasshi2525/RushHour/entities"
+	"github.com/yasshi2525/RushHour/storage"
 )
 
-// MigrateDB migrate database by reference Meta
-// POLICY:
-//   1. not allow nullable field because go cann't identify nil or zero value
-//      so, no foreign key restriction for nullable field
-//   2. id must be grater than 0. id: 0 means nil
-//   3. not use sql.NullInst64 for performance
-func MigrateDB() {
-	foreign := make(map[entities.ModelType]string)
+// SchemaVersion is step of schema migration applied to database.
+type SchemaVersion struct {
+	Version   uint
+	Name      string
+	AppliedAt time.Time
+}
 
-	db.AutoMigrate(&OpLog{})
+// schemaStep is numbered change of database schema.
+// Steps must not be modified after released. Add new step instead.
+type schemaStep struct {
+	version uint
+	name    string
+	// tables are created by the step. Existing ones are adopted as they are
+	// because tables were created without schema version at first.
+	tables []storage.Table
+	// columns are added to existing tables by the step.
+	columns []schemaColumn
+	// fill sets initial value of added columns or converts existing records.
+	fill func(tx storage.Tx) error
+}
 
-	// create instance corresponding to each record
-	for _, key := range entities.TypeList {
-		if !key.IsDB() {
+// schemaColumn is column added to existing table.
+type schemaColumn struct {
+	table string
+	storage.Column
+}
+
+// up applies the step.
+func (st schemaStep) up() error {
+	for _, t := range st.tables {
+		if store.HasTable(t.Name) {
 			continue
 		}
+		if err := store.CreateTable(t); err != nil {
+			return err
+		}
+	}
+	for _, c := range st.columns {
+		if err := store.AddColumn(c.table, c.Column); err != nil {
+			return err
+		}
+	}
+	if st.fill != nil {
+		tx, err := store.Begin()
+		if err != nil {
+			return err
+		}
+		if err := st.fill(tx); err != nil {
+			tx.Rollback()
+			return err
+		}
+		return tx.Commit()
+	}
+	return nil
+}
 
-		proto := key.Obj(Model)
-		db.AutoMigrate(proto)
+// down reverts the step. Referring tables are dropped first.
+func (st schemaStep) down() error {
+	prev := schemaAt(st.version - 1)
+	for i := len(st.columns) - 1; i >= 0; i-- {
+		c := st.columns[i]
+		if err := store.DropColumn(prev[c.table], c.Name); err != nil {
+			return err
+		}
+	}
+	for i := len(st.tables) - 1; i >= 0; i-- {
+		if err := store.DropTable(st.tables[i].Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-		// foreign key for owner
-		if proto.B().O != nil {
-			owner := fmt.Sprintf("%s(id)", entities.PLAYER.Table())
-			db.Model(proto).AddForeignKey("owner_id", owner, "RESTRICT", "RESTRICT")
+// schemaAt returns definition of tables when steps are applied until specified version.
+func schemaAt(version uint) map[string]storage.Table {
+	tables := make(map[string]storage.Table)
+	for _, st := range schemaSteps {
+		if st.version > version {
+			break
+		}
+		for _, t := range st.tables {
+			tables[t.Name] = t
 		}
+		for _, c := range st.columns {
+			t := tables[c.table]
+			t.Columns = append(append([]storage.Column{}, t.Columns...), c.Column)
+			tables[c.table] = t
+		}
+	}
+	return tables
+}
+
+// LatestSchema returns version which this server requires.
+func LatestSchema() uint {
+	return schemaSteps[len(schemaSteps)-1].version
+}
 
-		foreign[key] = fmt.Sprintf("%s(id)", key.Table())
+// CurrentSchema returns version of database.
+// 0 means no step is applied.
+func CurrentSchema() (uint, error) {
+	if !store.HasTable(schemaVersionTable.Name) {
+		if err := store.CreateTable(schemaVersionTable); err != nil {
+			return 0, err
+		}
 	}
+	v, err := store.Max(schemaVersionTable.Name, "version")
+	return uint(v), err
+}
 
-	// RailEdge connects RailNode
-	db.Model(entities.RAILEDGE.Obj(Model)).AddForeignKey("from_id", foreign[entities.RAILNODE], "CASCADE", "RESTRICT")
-	db.Model(entities.RAILEDGE.Obj(Model)).AddForeignKey("to_id", foreign[entities.RAILNODE], "CASCADE", "RESTRICT")
+// VerifySchema checks that database has all tables and columns of current version.
+func VerifySchema() error {
+	current, err := CurrentSchema()
+	if err != nil {
+		return err
+	}
+	missing := []string{}
+	tables := schemaAt(current)
+	names := []string{}
+	for name := range tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !store.HasTable(name) {
+			missing = append(missing, name)
+			continue
+		}
+		for _, c := range tables[name].Columns {
+			if !store.HasColumn(name, c.Name) {
+				missing = append(missing, fmt.Sprintf("%s.%s", name, c.Name))
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("schema version %d lacks %s", current, strings.Join(missing, ", "))
+	}
+	return nil
+}
 
-	// Station composes Platforms and Gates
-	db.Model(entities.PLATFORM.Obj(Model)).AddForeignKey("rail_node_id", foreign[entities.RAILNODE], "RESTRICT", "RESTRICT")
-	db.Model(entities.PLATFORM.Obj(Model)).AddForeignKey("station_id", foreign[entities.STATION], "CASCADE", "RESTRICT")
-	db.Model(entities.GATE.Obj(Model)).AddForeignKey("station_id", foreign[entities.STATION], "CASCADE", "RESTRICT")
+// recordSchema records that step was applied.
+func recordSchema(v *SchemaVersion) error {
+	tx, err := store.Begin()
+	if err != nil {
+		return err
+	}
+	columns, row := store.Columns(v)
+	if err := tx.Insert(schemaVersionTable.Name, columns, [][]interface{}{row}); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
 
-	// Line composes LineTasks
-	db.Model(entities.LINETASK.Obj(Model)).AddForeignKey("rail_line_id", foreign[entities.RAILLINE], "CASCADE", "RESTRICT")
+// eraseSchema records that step was reverted.
+func eraseSchema(version uint) error {
+	tx, err := store.Begin()
+	if err != nil {
+		return err
+	}
+	if err := tx.Delete(schemaVersionTable.Name, "version", version); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// MigrateDB applies steps not applied yet.
+// It fails when database is newer than this server.
+func MigrateDB() error {
+	current, err := CurrentSchema()
+	if err != nil {
+		return err
+	}
+	if current > LatestSchema() {
+		return fmt.Errorf("schema version %d of database is newer than %d", current, LatestSchema())
+	}
+	return MigrateSchema(LatestSchema())
+}
 
-	// Human departs from Residence and destinates to Company
-	db.Model(entities.HUMAN.Obj(Model)).AddForeignKey("from_id", foreign[entities.RESIDENCE], "RESTRICT", "RESTRICT")
-	db.Model(entities.HUMAN.Obj(Model)).AddForeignKey("to_id", foreign[entities.COMPANY], "RESTRICT", "RESTRICT")
+// MigrateSchema applies or reverts steps until database becomes specified version.
+func MigrateSchema(target uint) error {
+	if target > LatestSchema() {
+		return fmt.Errorf("schema version %d doesn't exist (latest %d)", target, LatestSchema())
+	}
+	current, err := CurrentSchema()
+	if err != nil {
+		return err
+	}
+	for _, st := range schemaSteps {
+		if st.version <= current || st.version > target {
+			continue
+		}
+		if err := st.up(); err != nil {
+			return fmt.Errorf("failed to apply schema %d (%s): %v", st.version, st.name, err)
+		}
+		if err := recordSchema(&SchemaVersion{st.version, st.name, time.Now()}); err != nil {
+			return err
+		}
+		log.Printf("schema %d (%s) was applied", st.version, st.name)
+	}
+	for i := len(schemaSteps) - 1; i >= 0; i-- {
+		st := schemaSteps[i]
+		if st.version > current || st.version <= target {
+			continue
+		}
+		if err := st.down(); err != nil {
+			return fmt.Errorf("failed to revert schema %d (%s): %v", st.version, st.name, err)
+		}
+		if err := eraseSchema(st.version); err != nil {
+			return err
+		}
+		log.Printf("schema %d (%s) was reverted", st.version, st.name)
+	}
+	return nil
 }
 
-// PurgeDB purge database without login user
-func PurgeDB(o *entities.Player) {
-	length := len(entities.TypeList)
-	tx := db.Begin()
-	for i := length - 1; i >= 0; i-- {
-		if key := entities.TypeList[i]; key.IsDB() {
-			if key == entities.PLAYER {
-				db.Exec(fmt.Sprintf("DELETE FROM %s WHERE login_id <> %s", key.Table(), o.LoginID))
-			} else {
-				db.Exec(fmt.Sprintf("DELETE FROM %s", key.Table()))
+// persistOrder returns types stored in database, where type of referred table precedes referring one.
+// References are foreign keys in latest schema. Types without dependency keep order of TypeList.
+func persistOrder() []entities.ModelType {
+	tables := schemaAt(LatestSchema())
+	types := make(map[string]entities.ModelType)
+	for _, key := range entities.TypeList {
+		if key.IsDB() {
+			types[key.Table()] = key
+		}
+	}
+	order := []entities.ModelType{}
+	visited := make(map[entities.ModelType]bool)
+	var visit func(key entities.ModelType)
+	visit = func(key entities.ModelType) {
+		if visited[key] {
+			return
+		}
+		visited[key] = true
+		for _, fk := range tables[key.Table()].ForeignKeys {
+			if dep, ok := types[fk.Table]; ok {
+				visit(dep)
+			}
+		}
+		order = append(order, key)
+	}
+	for _, key := range entities.TypeList {
+		if key.IsDB() {
+			visit(key)
+		}
+	}
+	return order
+}
+
+// PurgeDB purge database without login user.
+// All users are purged when o is nil.
+// Referring tables are purged first. Nothing is purged when it fails.
+func PurgeDB(o *entities.Player) error {
+	tx, err := store.Begin()
+	if err != nil {
+		return err
+	}
+	if err := purgeTables(tx, o); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// purgeTables deletes records of all tables within tx.
+func purgeTables(tx storage.Tx, o *entities.Player) error {
+	if err := tx.DeleteAll("scores"); err != nil {
+		return err
+	}
+	if err := tx.DeleteAll("clock_states"); err != nil {
+		return err
+	}
+	order := persistOrder()
+	for i := len(order) - 1; i >= 0; i-- {
+		key := order[i]
+		if key == entities.PLAYER && o != nil {
+			if err := tx.DeleteExcept(key.Table(), "login_id", o.LoginID); err != nil {
+				return err
 			}
+		} else if err := tx.DeleteAll(key.Table()); err != nil {
+			return err
 		}
 	}
-	tx.Commit()
+	return nil
 }
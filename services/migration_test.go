@@ -0,0 +1,181 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/yasshi2525/RushHour/auth"
+	"github.com/yasshi2525/RushHour/config"
+	"github.com/yasshi2525/RushHour/entities"
+	"github.com/yasshi2525/RushHour/storage"
+)
+
+func TestMigrateSchema(t *testing.T) {
+	wd, _ := os.Getwd()
+	conf, _ = config.Load(fmt.Sprintf("%s/../config", wd))
+	auther, _ = auth.GetAuther(conf.Secret.Auth)
+
+	InitLock()
+	InitRepository()
+	defer openTestDB(t)()
+
+	if got, _ := CurrentSchema(); got != LatestSchema() {
+		t.Errorf("CurrentSchema() got %d, want %d", got, LatestSchema())
+	}
+	// applied twice
+	if err := MigrateDB(); err != nil {
+		t.Errorf("MigrateDB() got %v, want nil", err)
+	}
+	if err := MigrateSchema(LatestSchema() + 1); err == nil {
+		t.Errorf("MigrateSchema(%d) got nil, want error", LatestSchema()+1)
+	}
+
+	if err := MigrateSchema(0); err != nil {
+		t.Fatalf("MigrateSchema(0) got %v, want nil", err)
+	}
+	if got, _ := CurrentSchema(); got != 0 {
+		t.Errorf("CurrentSchema() got %d, want 0", got)
+	}
+	if store.HasTable(entities.PLAYER.Table()) {
+		t.Errorf("HasTable(%s) got true, want false", entities.PLAYER.Table())
+	}
+
+	if err := MigrateSchema(LatestSchema()); err != nil {
+		t.Fatalf("MigrateSchema(%d) got %v, want nil", LatestSchema(), err)
+	}
+	if !store.HasTable(entities.PLAYER.Table()) {
+		t.Errorf("HasTable(%s) got false, want true", entities.PLAYER.Table())
+	}
+
+	if err := VerifySchema(); err != nil {
+		t.Errorf("VerifySchema() got %v, want nil", err)
+	}
+	players := schemaAt(LatestSchema())[entities.PLAYER.Table()]
+	columns := players.Columns[:0:0]
+	for _, c := range players.Columns {
+		if c.Name != "revenue" {
+			columns = append(columns, c)
+		}
+	}
+	players.Columns = columns
+	if err := store.DropColumn(players, "revenue"); err != nil {
+		t.Fatalf("DropColumn() got %v, want nil", err)
+	}
+	if err := VerifySchema(); err == nil {
+		t.Errorf("VerifySchema() without column got nil, want error")
+	}
+
+	// foreign key is declared in table
+	tx, _ := store.Begin()
+	err := tx.Insert(entities.RAILEDGE.Table(), []string{"from_id", "to_id", "reverse_id"}, [][]interface{}{{999, 999, 0}})
+	tx.Rollback()
+	if err == nil {
+		t.Errorf("Insert(%s) referring no RailNode got nil, want error", entities.RAILEDGE.Table())
+	}
+
+	recordSchema(&SchemaVersion{LatestSchema() + 1, "future", time.Now()})
+	if err := MigrateDB(); err == nil {
+		t.Errorf("MigrateDB() got nil, want error for newer database")
+	}
+}
+
+func TestSchemaDefinition(t *testing.T) {
+	wd, _ := os.Getwd()
+	conf, _ = config.Load(fmt.Sprintf("%s/../config", wd))
+	auther, _ = auth.GetAuther(conf.Secret.Auth)
+
+	InitLock()
+	InitRepository()
+	defer openTestDB(t)()
+
+	// latest schema must have all columns which entities persist
+	tables := schemaAt(LatestSchema())
+	objs := map[string]interface{}{"op_logs": &OpLog{}, "scores": &Score{}, "clock_states": &ClockState{}}
+	for _, key := range entities.TypeList {
+		if key.IsDB() {
+			objs[key.Table()] = key.Obj(Model)
+		}
+	}
+	for name, obj := range objs {
+		columns, _ := store.Columns(obj)
+		for _, c := range columns {
+			if _, ok := tables[name].Column(c); !ok {
+				t.Errorf("schema of %s lacks %s", name, c)
+			}
+		}
+	}
+}
+
+func TestMigrateMoney(t *testing.T) {
+	wd, _ := os.Getwd()
+	conf, _ = config.Load(fmt.Sprintf("%s/../config", wd))
+	conf.Game.Economy.Initial = 12345
+	auther, _ = auth.GetAuther(conf.Secret.Auth)
+
+	InitLock()
+	InitRepository()
+	defer openTestDB(t)()
+
+	// player created before money was introduced
+	if err := MigrateSchema(1); err != nil {
+		t.Fatalf("MigrateSchema(1) got %v, want nil", err)
+	}
+	columns, row := []string{}, []interface{}{}
+	for _, c := range schemaAt(1)[entities.PLAYER.Table()].Columns {
+		switch c.Type {
+		case storage.ID:
+			continue
+		case storage.String, storage.Text:
+			row = append(row, "")
+		case storage.Bool:
+			row = append(row, false)
+		case storage.Time:
+			row = append(row, time.Now())
+		default:
+			row = append(row, 0)
+		}
+		columns = append(columns, c.Name)
+	}
+	tx, _ := store.Begin()
+	if err := tx.Insert(entities.PLAYER.Table(), columns, [][]interface{}{row}); err != nil {
+		t.Fatalf("Insert() got %v, want nil", err)
+	}
+	tx.Commit()
+
+	if err := MigrateDB(); err != nil {
+		t.Fatalf("MigrateDB() got %v, want nil", err)
+	}
+	if got, _ := store.Max(entities.PLAYER.Table(), "money"); got != 12345 {
+		t.Errorf("money got %d, want 12345", got)
+	}
+}
+
+func TestMigrateScoreStamp(t *testing.T) {
+	wd, _ := os.Getwd()
+	conf, _ = config.Load(fmt.Sprintf("%s/../config", wd))
+	auther, _ = auth.GetAuther(conf.Secret.Auth)
+
+	InitLock()
+	InitRepository()
+	defer openTestDB(t)()
+
+	// score stamped by wall clock before game clock was introduced
+	if err := MigrateSchema(6); err != nil {
+		t.Fatalf("MigrateSchema(6) got %v, want nil", err)
+	}
+	columns, row := store.Columns(&Score{OwnerID: 1, TimeStamp: time.Now()}, "id")
+	tx, _ := store.Begin()
+	if err := tx.Insert("scores", columns, [][]interface{}{row}); err != nil {
+		t.Fatalf("Insert() got %v, want nil", err)
+	}
+	tx.Commit()
+
+	if err := MigrateDB(); err != nil {
+		t.Fatalf("MigrateDB() got %v, want nil", err)
+	}
+	if got, _ := store.Max("scores", "owner_id"); got != 0 {
+		t.Errorf("score stamped by wall clock got owner %d, want discarded", got)
+	}
+}
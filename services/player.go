@@ -17,6 +17,7 @@ func CreatePlayer(loginid string, displayname string, password string, hue int,
 	o.UseCustomDisplayName = true
 	o.Hue = hue
 	o.UseCustomImage = true
+	initMoney(o)
 	AddOpLog("CreatePlayer", o)
 	return o, nil
 
@@ -24,9 +25,13 @@ func CreatePlayer(loginid string, displayname string, password string, hue int,
 
 // OAuthSignIn find or create Player by OAuth
 func OAuthSignIn(authType entities.AuthType, info *auth.OAuthInfo) (*entities.Player, error) {
+	_, exists := Model.Logins[authType][auther.Digest(info.LoginID)]
 	if o, err := Model.OAuthSignIn(authType, info); err != nil {
 		return nil, err
 	} else {
+		if !exists {
+			initMoney(o)
+		}
 		return o, nil
 	}
 }
@@ -55,6 +60,7 @@ func PasswordSignUp(loginid string, name string, password string, hue int, lv en
 	o.UseCustomDisplayName = true
 	o.Hue = hue
 	o.UseCustomImage = true
+	initMoney(o)
 	return o, nil
 
 }
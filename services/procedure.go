@@ -7,6 +7,7 @@ import (
 
 var gamemaster *time.Ticker
 var beforeProcedure time.Time
+var beforeReweight time.Time
 
 // StartProcedure start game.
 func StartProcedure() {
@@ -30,20 +31,56 @@ func watchGame() {
 	}
 }
 
+// processGame advances simulation with exclusive lock,
+// then publishes the result as View so that viewers are not blocked by next procedure.
 func processGame() {
 	start := time.Now()
+	if stepped, lock := advanceGame(); stepped {
+		publishGame()
+		WarnLongExec(start, lock, conf.Game.Service.Perf.Game.D, "procedure")
+	}
+}
+
+// advanceGame changes Model by elapsed time since previous procedure.
+// It returns false at first procedure because there is no elapsed time.
+func advanceGame() (bool, time.Time) {
 	MuModel.Lock()
 	defer MuModel.Unlock()
 	lock := time.Now()
-	defer WarnLongExec(start, lock, conf.Game.Service.Perf.Game.D, "procedure")
 	defer func() { beforeProcedure = time.Now() }()
 
 	if beforeProcedure.IsZero() {
-		return
+		return false, lock
+	}
+	n := GameClock.advance(time.Now().Sub(beforeProcedure))
+	if n == 0 {
+		// route is applied while game is paused as well
+		applyRouting()
 	}
-	interval := time.Now().Sub(beforeProcedure).Seconds()
+	StepGame(n)
+	processReweight(time.Now())
+	processFare()
+	processScore(GameClock.Now())
+	Model.PruneTombstones(time.Now().Add(-conf.Game.Service.GameMap.Retention.D))
+	return true, lock
+}
+
+// publishGame publishes View for readers and pushes difference of viewport to subscribers.
+// Subscribers are pushed from View, so they don't wait for lock of MuModel.
+func publishGame() {
+	MuModel.RLock()
+	PublishView()
+	MuModel.RUnlock()
 
-	for _, t := range Model.Trains {
-		t.Step(interval)
+	broadcastMap()
+}
+
+// processReweight reflects congestion to route periodically.
+func processReweight(now time.Time) {
+	interval := conf.Game.Service.Routing.Congestion.Interval.D
+	if interval == 0 || now.Sub(beforeReweight) < interval {
+		return
 	}
+	beforeReweight = now
+	ReweightRouting()
 }
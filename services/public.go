@@ -15,7 +15,7 @@ func CreateResidence(o *entities.Player, x float64, y float64) (*entities.Reside
 	r := Model.NewResidence(x, y)
 	r.Name = "NoName"
 
-	StartRouting()
+	UpdateRouting()
 	AddOpLog("CreateResidence", o, r)
 	return r, nil
 }
@@ -25,19 +25,33 @@ func RemoveResidence(o *entities.Player, id uint) error {
 	if r, err := Model.DeleteIf(o, entities.RESIDENCE, id); err != nil {
 		return err
 	} else {
-		StartRouting()
+		UpdateRouting()
 		AddOpLog("RemoveResidence", o, r)
 		return nil
 	}
 }
 
+// ChangeResidenceDemand changes multiplier of Human generation of Residence.
+func ChangeResidenceDemand(o *entities.Player, r *entities.Residence, v float64) error {
+	if o.Level != entities.Admin {
+		return fmt.Errorf("no permission")
+	}
+	if v < 0 {
+		return fmt.Errorf("demand must be gte 0: %f", v)
+	}
+	r.Demand = v
+	r.Change()
+	AddOpLog("ChangeResidenceDemand", o, r)
+	return nil
+}
+
 // CreateCompany creates Company and registers it to storage and step
 func CreateCompany(o *entities.Player, x float64, y float64) (*entities.Company, error) {
 	if o.Level != entities.Admin {
 		return nil, fmt.Errorf("no permission")
 	}
 	c := Model.NewCompany(x, y)
-	StartRouting()
+	UpdateRouting()
 	AddOpLog("CreateCompany", o, c)
 	return c, nil
 }
@@ -47,8 +61,22 @@ func RemoveCompany(o *entities.Player, id uint) error {
 	if c, err := Model.DeleteIf(o, entities.COMPANY, id); err != nil {
 		return err
 	} else {
-		StartRouting()
+		UpdateRouting()
 		AddOpLog("RemoveCompany", o, c)
 		return nil
 	}
 }
+
+// ChangeCompanyDemand changes multiplier of commuters Company draws.
+func ChangeCompanyDemand(o *entities.Player, c *entities.Company, v float64) error {
+	if o.Level != entities.Admin {
+		return fmt.Errorf("no permission")
+	}
+	if v < 0 {
+		return fmt.Errorf("demand must be gte 0: %f", v)
+	}
+	c.Demand = v
+	c.Change()
+	AddOpLog("ChangeCompanyDemand", o, c)
+	return nil
+}
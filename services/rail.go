@@ -4,13 +4,13 @@ import (
 	"fmt"
 
 	"github.com/yasshi2525/RushHour/entities"
-	"github.com/yasshi2525/RushHour/route"
 )
 
 // CreateRailNode create RailNode
 func CreateRailNode(o *entities.Player, x float64, y float64, scale int) (*entities.DelegateRailNode, error) {
 	rn := Model.NewRailNode(o, x, y)
-	StartRouting()
+	requestRefresh(o)
+	UpdateRouting()
 	AddOpLog("CreateRailNode", o, rn)
 
 	if ch := Model.RootCluster.FindChunk(rn, scale); ch != nil {
@@ -25,15 +25,8 @@ func RemoveRailNode(o *entities.Player, id uint) error {
 		return err
 	} else {
 		rn := rn.(*entities.RailNode)
-		if o.ReRouting {
-			route.RefreshTracks(o, conf.Game.Service.Routing.Worker)
-		}
-		for _, l := range o.RailLines {
-			if l.ReRouting {
-				route.RefreshTransports(l, conf.Game.Service.Routing.Worker)
-			}
-		}
-		StartRouting()
+		requestRefresh(o)
+		UpdateRouting()
 		AddOpLog("RemoveRailNode", o, rn)
 		return nil
 	}
@@ -45,14 +38,14 @@ func ExtendRailNode(o *entities.Player, from *entities.RailNode,
 	if err := CheckAuth(o, from); err != nil {
 		return nil, nil, err
 	}
-	to, e1 := from.Extend(x, y)
-	route.RefreshTracks(o, conf.Game.Service.Routing.Worker)
-	for _, l := range o.RailLines {
-		if l.ReRouting {
-			route.RefreshTransports(l, conf.Game.Service.Routing.Worker)
-		}
+	cost := railCost(&from.Point, &entities.Point{X: x, Y: y})
+	if err := o.CanPay(cost); err != nil {
+		return nil, nil, err
 	}
-	StartRouting()
+	to, e1 := from.Extend(x, y)
+	o.Pay(cost)
+	requestRefresh(o)
+	UpdateRouting()
 	AddOpLog("ExtendRailNode", o, from, to, e1, e1.Reverse)
 
 	fch := Model.RootCluster.FindChunk(from, scale)
@@ -79,9 +72,14 @@ func ConnectRailNode(o *entities.Player, from *entities.RailNode, to *entities.R
 			return nil, fmt.Errorf("already conntected")
 		}
 	}
+	cost := railCost(&from.Point, &to.Point)
+	if err := o.CanPay(cost); err != nil {
+		return nil, err
+	}
 	e1 := from.Connect(to)
-	route.RefreshTracks(o, conf.Game.Service.Routing.Worker)
-	StartRouting()
+	o.Pay(cost)
+	requestRefresh(o)
+	UpdateRouting()
 	AddOpLog("ConnectRailNode", o, from, to, e1, e1.Reverse)
 
 	fch := Model.RootCluster.FindChunk(from, scale)
@@ -98,15 +96,8 @@ func RemoveRailEdge(o *entities.Player, id uint) error {
 		return err
 	} else {
 		re := re.(*entities.RailEdge)
-		if o.ReRouting {
-			route.RefreshTracks(o, conf.Game.Service.Routing.Worker)
-		}
-		for _, l := range o.RailLines {
-			if l.ReRouting {
-				route.RefreshTransports(l, conf.Game.Service.Routing.Worker)
-			}
-		}
-		StartRouting()
+		requestRefresh(o)
+		UpdateRouting()
 		AddOpLog("RemoveRailEdge", o, re)
 		return nil
 	}
@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"github.com/yasshi2525/RushHour/entities"
-	"github.com/yasshi2525/RushHour/route"
 )
 
 // CreateRailLine create RailLine
@@ -16,7 +15,7 @@ func CreateRailLine(o *entities.Player, name string, ext bool, pass bool) (*enti
 	l.AutoExt = ext
 	l.AutoPass = pass
 
-	StartRouting()
+	UpdateRouting()
 	AddOpLog("CreateRailLine", o, l)
 	return l, nil
 }
@@ -36,10 +35,8 @@ func StartRailLine(
 		return fmt.Errorf("task is already registered: %v", l)
 	}
 	l.StartPlatform(p)
-	if l.ReRouting {
-		route.RefreshTransports(l, conf.Game.Service.Routing.Worker)
-	}
-	StartRouting()
+	requestRefresh(o)
+	UpdateRouting()
 	AddOpLog("StartRailLine", o, l, p)
 	return nil
 }
@@ -57,15 +54,16 @@ func StartRailLineEdge(o *entities.Player,
 		return fmt.Errorf("task is already registered: %v", l)
 	}
 	l.StartEdge(re)
-	if l.ReRouting {
-		route.RefreshTransports(l, conf.Game.Service.Routing.Worker)
-	}
-	StartRouting()
+	requestRefresh(o)
+	UpdateRouting()
 	AddOpLog("StartRailLineEdge", o, l, re)
 	return nil
 }
 
 func InsertLineTaskRailEdge(o *entities.Player, l *entities.RailLine, re *entities.RailEdge) error {
+	if err := CheckAuth(o, l); err != nil {
+		return err
+	}
 	if err := CheckAuth(o, re); err != nil {
 		return err
 	}
@@ -74,10 +72,8 @@ func InsertLineTaskRailEdge(o *entities.Player, l *entities.RailLine, re *entiti
 			lt.InsertRailEdge(re)
 		}
 	}
-	if l.ReRouting {
-		route.RefreshTransports(l, conf.Game.Service.Routing.Worker)
-	}
-	StartRouting()
+	requestRefresh(o)
+	UpdateRouting()
 	AddOpLog("InsertLineTaskRailEdge", o, l, re)
 	return nil
 }
@@ -90,7 +86,8 @@ func ComplementRailLine(o *entities.Player, l *entities.RailLine) (bool, error)
 		return false, fmt.Errorf("line is already ringed: %v", l)
 	}
 	l.Complement()
-	StartRouting()
+	requestRefresh(o)
+	UpdateRouting()
 	return true, nil
 }
 
@@ -102,8 +99,9 @@ func RingRailLine(o *entities.Player, l *entities.RailLine) (bool, error) {
 	// Check RainLine is not ringing
 	ret := l.RingIf()
 	if ret {
-		route.RefreshTransports(l, conf.Game.Service.Routing.Worker)
-		StartRouting()
+		l.ReRouting = true
+		requestRefresh(o)
+		UpdateRouting()
 		AddOpLog("RingRailLine", o, l)
 	}
 	return ret, nil
@@ -113,7 +111,7 @@ func RemoveRailLine(o *entities.Player, id uint) error {
 	if l, err := Model.DeleteIf(o, entities.RAILLINE, id); err != nil {
 		return err
 	} else {
-		StartRouting()
+		UpdateRouting()
 		AddOpLog("RemoveRailLine", o, l)
 		return nil
 	}
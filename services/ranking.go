@@ -0,0 +1,179 @@
+package services
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/yasshi2525/RushHour/entities"
+)
+
+// Score is snapshot of Player statistics.
+// TimeStamp is simulated time of GameClock, not wall clock.
+type Score struct {
+	gorm.Model
+	OwnerID     uint      `gorm:"not null;index"`
+	Delivered   int64     `gorm:"not null"`
+	Commuters   int64     `gorm:"not null"`
+	CommuteTime float64   `gorm:"not null"`
+	Revenue     int64     `gorm:"not null"`
+	Network     float64   `gorm:"not null"`
+	TimeStamp   time.Time `gorm:"not null;index"`
+}
+
+// ScoreHistory is snapshots within retention period ordered by TimeStamp
+var ScoreHistory []*Score
+
+// ScoreCache is snapshots which are not persisted yet
+var ScoreCache []*Score
+
+var beforeScore time.Time
+
+// RankingKey represents statistics which ranking is sorted by
+const (
+	RankByDelivered = "delivered"
+	RankByCommute   = "commute"
+	RankByRevenue   = "revenue"
+	RankByNetwork   = "network"
+)
+
+// Rank is statistics of Player within specified period
+type Rank struct {
+	Rank int `json:"rank"`
+	// Player is serialized Player when View is published
+	Player json.RawMessage `json:"player"`
+	// ownerID is id of Player
+	ownerID uint
+	// Delivered is the number of passengers
+	Delivered int64 `json:"delivered"`
+	// CommuteTime is average seconds of commuters from Residence to Company
+	CommuteTime float64 `json:"commute_time"`
+	Revenue     int64   `json:"revenue"`
+	// Network is current length of rail
+	Network float64 `json:"network"`
+}
+
+func newScore(o *entities.Player, now time.Time) *Score {
+	return &Score{
+		OwnerID:     o.ID,
+		Delivered:   o.Delivered,
+		Commuters:   o.Commuters,
+		CommuteTime: o.CommuteTime,
+		Revenue:     o.Revenue,
+		Network:     o.NetworkLength(),
+		TimeStamp:   now,
+	}
+}
+
+// processScore takes snapshot of each Player at regular interval of simulated time
+func processScore(now time.Time) {
+	if !beforeScore.IsZero() && now.Sub(beforeScore) < conf.Game.Service.Ranking.Interval.D {
+		return
+	}
+	beforeScore = now
+	for _, o := range Model.Players {
+		s := newScore(o, now)
+		ScoreHistory = append(ScoreHistory, s)
+		if conf.Game.Service.Backup.Enabled {
+			ScoreCache = append(ScoreCache, s)
+		}
+	}
+	limit := now.Add(-conf.Game.Service.Ranking.Retention.D)
+	idx := sort.Search(len(ScoreHistory), func(i int) bool {
+		return !ScoreHistory[i].TimeStamp.Before(limit)
+	})
+	ScoreHistory = ScoreHistory[idx:]
+}
+
+// baseScores returns the latest snapshot of each Player before start in history.
+// When there is no snapshot before start, the oldest one is used instead.
+func baseScores(history []*Score, start time.Time) map[uint]*Score {
+	bases := make(map[uint]*Score)
+	for _, s := range history {
+		if !s.TimeStamp.After(start) {
+			bases[s.OwnerID] = s
+		} else if _, found := bases[s.OwnerID]; !found {
+			bases[s.OwnerID] = s
+		}
+	}
+	return bases
+}
+
+// Ranking returns statistics of Players sorted by key within window (0 means all-time).
+// window is measured by simulated time of GameClock.
+// It returns ranks in [offset, offset+limit) and the total number of Players.
+// It is calculated from published View, so it doesn't need lock of MuModel.
+func Ranking(key string, window time.Duration, offset int, limit int) ([]*Rank, int) {
+	return CurrentView().ranking(key, window, offset, limit)
+}
+
+// ranking calculates Ranking with statistics when View is published.
+func (v *View) ranking(key string, window time.Duration, offset int, limit int) ([]*Rank, int) {
+	var bases map[uint]*Score
+	if window > 0 {
+		bases = baseScores(v.history, v.now.Add(-window))
+	} else {
+		bases = make(map[uint]*Score)
+	}
+
+	list := []*Rank{}
+	for _, cur := range v.scores {
+		base := &Score{}
+		if s, found := bases[cur.OwnerID]; found {
+			base = s
+		}
+		r := &Rank{
+			Player:    v.player[cur.OwnerID],
+			ownerID:   cur.OwnerID,
+			Delivered: cur.Delivered - base.Delivered,
+			Revenue:   cur.Revenue - base.Revenue,
+			Network:   cur.Network,
+		}
+		if num := cur.Commuters - base.Commuters; num > 0 {
+			r.CommuteTime = (cur.CommuteTime - base.CommuteTime) / float64(num)
+		}
+		list = append(list, r)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		switch key {
+		case RankByCommute:
+			// shorter is better and no commuter is worst
+			if a.CommuteTime != b.CommuteTime {
+				if a.CommuteTime == 0 || b.CommuteTime == 0 {
+					return b.CommuteTime == 0
+				}
+				return a.CommuteTime < b.CommuteTime
+			}
+		case RankByRevenue:
+			if a.Revenue != b.Revenue {
+				return a.Revenue > b.Revenue
+			}
+		case RankByNetwork:
+			if a.Network != b.Network {
+				return a.Network > b.Network
+			}
+		default:
+			if a.Delivered != b.Delivered {
+				return a.Delivered > b.Delivered
+			}
+		}
+		return a.ownerID < b.ownerID
+	})
+	for i, r := range list {
+		r.Rank = i + 1
+	}
+
+	total := len(list)
+	if offset > total {
+		offset = total
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	return list[offset:end], total
+}
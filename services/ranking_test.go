@@ -0,0 +1,71 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/yasshi2525/RushHour/auth"
+	"github.com/yasshi2525/RushHour/config"
+	"github.com/yasshi2525/RushHour/entities"
+)
+
+func TestRanking(t *testing.T) {
+	wd, _ := os.Getwd()
+	conf, _ = config.Load(fmt.Sprintf("%s/../config", wd))
+	conf.Game.Service.Routing.Worker = 1
+	conf.Game.Entity.MaxScale = 2
+	auther, _ = auth.GetAuther(conf.Secret.Auth)
+
+	InitRepository()
+	isInOperation = true
+
+	o1, _ := CreatePlayer("test1", "test1", "test1", 0, entities.Normal)
+	o2, _ := CreatePlayer("test2", "test2", "test2", 0, entities.Normal)
+
+	o1.Delivered, o1.Revenue = 10, 100
+	o2.Delivered, o2.Revenue = 5, 200
+	processScore(GameClock.Now())
+	// window is measured by simulated time regardless of wall clock
+	GameClock.Ticks += uint64(2 * time.Hour / stepDuration())
+	o2.Delivered = 20
+	o1.Commute(30)
+	o2.Commute(60)
+	// ranking is calculated from published View
+	PublishView()
+
+	if list, total := Ranking(RankByDelivered, 0, 0, 10); total != 2 || list[0].ownerID != o2.ID {
+		t.Errorf("all-time delivered ranking should be led by o2, but %v", string(list[0].Player))
+	}
+	if list, _ := Ranking(RankByDelivered, time.Hour, 0, 10); list[0].Delivered != 16 || list[1].Delivered != 1 {
+		t.Errorf("hourly delivered should be (16, 1), but (%d, %d)", list[0].Delivered, list[1].Delivered)
+	}
+	if list, _ := Ranking(RankByRevenue, time.Hour, 0, 10); list[0].ownerID != o1.ID || list[0].Revenue != 0 {
+		t.Errorf("hourly revenue ranking should be led by o1 with tie, but %v", string(list[0].Player))
+	}
+	if list, _ := Ranking(RankByCommute, time.Hour, 0, 10); list[0].ownerID != o1.ID || list[0].CommuteTime != 30 {
+		t.Errorf("commute ranking should be led by o1 with 30, but %v", list[0])
+	}
+	if list, total := Ranking(RankByDelivered, 0, 1, 10); total != 2 || len(list) != 1 || list[0].Rank != 2 {
+		t.Errorf("second page should contain rank 2 only, but %v", list)
+	}
+	if list, _ := Ranking(RankByDelivered, 0, 5, 10); len(list) != 0 {
+		t.Errorf("out of range page should be empty, but %v", list)
+	}
+
+	o1.Delivered = 100
+	if list, _ := Ranking(RankByDelivered, 0, 0, 10); list[0].ownerID != o2.ID {
+		t.Errorf("ranking should be kept until View is published, but %s", string(list[0].Player))
+	}
+
+	processScore(GameClock.Now())
+	if got := ScoreHistory[len(ScoreHistory)-1].TimeStamp; !got.Equal(GameClock.Now()) {
+		t.Errorf("score should be stamped by GameClock %v, but %v", GameClock.Now(), got)
+	}
+	GameClock.Ticks += uint64(2 * time.Hour / stepDuration())
+	PublishView()
+	if list, _ := Ranking(RankByDelivered, time.Hour, 0, 10); list[0].Delivered != 0 {
+		t.Errorf("hourly delivered should be 0 after the latest score, but %d", list[0].Delivered)
+	}
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/yasshi2525/RushHour/entities"
+	"github.com/yasshi2525/RushHour/route"
+)
+
+// pendingTracks is Player waiting for refreshing tracks.
+// key is id of Player and value is generation of last request.
+var pendingTracks map[uint]uint64
+
+// pendingLines is RailLine waiting for refreshing Transports.
+// key is id of RailLine and value is generation of last request.
+var pendingLines map[uint]uint64
+
+var refreshGen uint64
+var refreshing bool
+
+// refreshBackoff is the first and maxRefreshBackoff is the longest wait before retrying canceled search.
+const (
+	refreshBackoff    = 10 * time.Millisecond
+	maxRefreshBackoff = 5 * time.Second
+)
+
+// refreshJob is route.Job with its target and generation.
+type refreshJob struct {
+	*route.Job
+	o   *entities.Player
+	l   *entities.RailLine
+	gen uint64
+	ok  bool
+}
+
+// RoutingStatus represents progress of routing.
+type RoutingStatus struct {
+	// Searching is true while route of Human is searched from scratch
+	Searching bool `json:"searching"`
+	// Pending is the number of Players and RailLines waiting for refresh
+	Pending int `json:"pending"`
+}
+
+// GetRoutingStatus returns progress of routing.
+// It must be called with lock of MuModel.
+func GetRoutingStatus() *RoutingStatus {
+	return &RoutingStatus{searching, len(pendingTracks) + len(pendingLines)}
+}
+
+// initRefresh discards requests for previous Model.
+func initRefresh() {
+	pendingTracks = make(map[uint]uint64)
+	pendingLines = make(map[uint]uint64)
+}
+
+// requestRefresh refreshes tracks of Player and Transports of its RailLines marked as ReRouting in background.
+// Requests for same Player or RailLine are coalesced until searching of them starts.
+// It must be called with lock of MuModel after an operation.
+func requestRefresh(o *entities.Player) {
+	if o.ReRouting {
+		refreshGen++
+		pendingTracks[o.ID] = refreshGen
+	}
+	for _, l := range o.RailLines {
+		if l.ReRouting {
+			refreshGen++
+			pendingLines[l.ID] = refreshGen
+		}
+	}
+	if !refreshing && (len(pendingTracks) > 0 || len(pendingLines) > 0) {
+		refreshing = true
+		go processRefresh()
+	}
+}
+
+// processRefresh searches tracks and Transports without lock until no request remains.
+// Search canceled because Pool was replaced is retried with backoff not to contend for MuModel.
+// It ends when Pool was stopped and requests are kept until next requestRefresh.
+func processRefresh() {
+	wait := refreshBackoff
+	for {
+		jobs := scanRefresh()
+		if jobs == nil {
+			return
+		}
+		canceled := false
+		for _, j := range jobs {
+			j.ok = j.Search(context.Background())
+			canceled = canceled || !j.ok
+		}
+		applyRefresh(jobs)
+		if !canceled {
+			wait = refreshBackoff
+			continue
+		}
+		if route.PoolStopped() {
+			endRefresh()
+			return
+		}
+		time.Sleep(wait)
+		if wait *= 2; wait > maxRefreshBackoff {
+			wait = maxRefreshBackoff
+		}
+	}
+}
+
+// endRefresh stops refreshing before all requests are reflected.
+func endRefresh() {
+	MuModel.Lock()
+	defer MuModel.Unlock()
+
+	refreshing = false
+}
+
+// scanRefresh creates job for each request.
+// It returns nil and ends refreshing when no request remains.
+func scanRefresh() []*refreshJob {
+	MuModel.Lock()
+	defer MuModel.Unlock()
+
+	if len(pendingTracks) == 0 && len(pendingLines) == 0 {
+		refreshing = false
+		return nil
+	}
+	jobs := []*refreshJob{}
+	for id, gen := range pendingTracks {
+		if o, ok := Model.Players[id]; ok {
+			jobs = append(jobs, &refreshJob{Job: route.NewTracksJob(o), o: o, gen: gen})
+		} else {
+			delete(pendingTracks, id)
+		}
+	}
+	for id, gen := range pendingLines {
+		if l, ok := Model.RailLines[id]; ok {
+			jobs = append(jobs, &refreshJob{Job: route.NewTransportsJob(l), l: l, gen: gen})
+		} else {
+			delete(pendingLines, id)
+		}
+	}
+	return jobs
+}
+
+// applyRefresh reflects result of jobs to Model.
+// Result is discarded when its target was requested again or removed while searching.
+func applyRefresh(jobs []*refreshJob) {
+	MuModel.Lock()
+	defer MuModel.Unlock()
+
+	for _, j := range jobs {
+		if !j.ok {
+			continue
+		}
+		if j.o != nil {
+			if gen, ok := pendingTracks[j.o.ID]; ok && gen == j.gen && Model.Players[j.o.ID] == j.o {
+				j.Apply()
+				delete(pendingTracks, j.o.ID)
+			}
+		} else {
+			if gen, ok := pendingLines[j.l.ID]; ok && gen == j.gen && Model.RailLines[j.l.ID] == j.l {
+				j.Apply()
+				delete(pendingLines, j.l.ID)
+			}
+		}
+	}
+	UpdateRouting()
+	PublishView()
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/yasshi2525/RushHour/auth"
+	"github.com/yasshi2525/RushHour/config"
+	"github.com/yasshi2525/RushHour/entities"
+	"github.com/yasshi2525/RushHour/route"
+)
+
+func TestRequestRefresh(t *testing.T) {
+	wd, _ := os.Getwd()
+	conf, _ = config.Load(fmt.Sprintf("%s/../config", wd))
+	conf.Game.Service.Routing.Worker = 1
+	conf.Game.Economy.Initial = 1000000
+	auther, _ = auth.GetAuther(conf.Secret.Auth)
+	InitLock()
+	InitRepository()
+
+	MuModel.Lock()
+	o, _ := CreatePlayer("test", "test", "test", 0, entities.Normal)
+	CreateRailNode(o, 0, 0, 0)
+	var rn *entities.RailNode
+	for _, x := range Model.RailNodes {
+		rn = x
+	}
+	ExtendRailNode(o, rn, 10, 0, 0)
+	if got := GetRoutingStatus().Pending; got != 1 {
+		t.Errorf("Pending got %d, want 1 (coalesced)", got)
+	}
+	if !o.ReRouting {
+		t.Errorf("ReRouting got false, want true before refresh")
+	}
+	MuModel.Unlock()
+
+	waitRefresh(t)
+	MuModel.Lock()
+	defer MuModel.Unlock()
+	if o.ReRouting {
+		t.Errorf("ReRouting got true, want false after refresh")
+	}
+	if got := len(rn.Tracks); got != 1 {
+		t.Errorf("len(Tracks) got %d, want 1", got)
+	}
+
+	// refreshing ends without retrying while Pool is stopped
+	route.StopPool()
+	defer route.InitPool(conf.Game.Service.Routing.Worker)
+	ExtendRailNode(o, rn, 0, 10, 0)
+	MuModel.Unlock()
+	waitRefresh(t)
+	MuModel.Lock()
+	if !o.ReRouting || GetRoutingStatus().Pending != 1 {
+		t.Errorf("request got (%v, %d), want kept until Pool restarts", o.ReRouting, GetRoutingStatus().Pending)
+	}
+
+	route.InitPool(conf.Game.Service.Routing.Worker)
+	requestRefresh(o)
+	MuModel.Unlock()
+	waitRefresh(t)
+	MuModel.Lock()
+	if o.ReRouting {
+		t.Errorf("ReRouting got true, want false after Pool restarts")
+	}
+}
+
+// waitRefresh blocks until all requests are reflected and refreshing ends.
+func waitRefresh(t *testing.T) {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		MuModel.Lock()
+		done := !refreshing
+		MuModel.Unlock()
+		if done {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("refresh didn't end")
+}
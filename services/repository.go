@@ -14,10 +14,16 @@ import (
 // Model is contained all data for gaming
 var Model *entities.Model
 
-// RouteTemplate is default route information in order to avoid huge calculation.
-var RouteTemplate *route.Payload
+// RouteTemplate is default route information for each type of goal in order to avoid huge calculation.
+var RouteTemplate map[entities.ModelType]*route.Payload
 
-// MuModel is mutex lock for Model
+// MuModel is mutex lock for Model.
+// Viewers share read lock, so that they must not change Model and states derived from it.
+// Players, game status, game map and ranking are read from published View without this lock.
+// Journeys are planned on route with lock of muJourney instead.
+// Operations and simulation steps lock it exclusively.
+// Writes aren't partitioned by Player or region because entities of different owners
+// refer to each other, such as Human riding Train and Steps over all Residences.
 var MuModel sync.RWMutex
 
 // MuRoute is mutex lock for routing
@@ -72,4 +78,13 @@ func InitLock() {
 func InitRepository() {
 	Model = entities.NewModel(conf.Game.Entity, auther)
 	OpCache = []*OpLog{}
+	ScoreHistory = []*Score{}
+	ScoreCache = []*Score{}
+	beforeScore = time.Time{}
+	GameClock = newClock()
+	initJourney()
+	pendingRouting = nil
+	initRefresh()
+	initBackup()
+	initView()
 }
@@ -1,7 +1,6 @@
 package services
 
 import (
-	"fmt"
 	"log"
 	"reflect"
 	"time"
@@ -28,11 +27,14 @@ func Restore(withLock bool) {
 
 	setNextID()
 	fetchStatic()
-	resolveStatic()
+	resolveStatic(Model)
 	for _, l := range Model.RailLines {
 		lineValidation(l) // [DEBUG]
 	}
-	genDynamics()
+	genDynamics(Model)
+	// scores within retention period are decided by GameClock
+	fetchClock()
+	fetchScore()
 }
 
 // setNextID set max id as NextID from database for Restore()
@@ -41,12 +43,8 @@ func setNextID() {
 		if !key.IsDB() {
 			continue
 		}
-		var maxID struct {
-			V uint64
-		}
-		sql := fmt.Sprintf("SELECT max(id) as v FROM %s", key.Table())
-		if err := db.Raw(sql).Scan(&maxID).Error; err == nil {
-			Model.NextIDs[key] = &maxID.V
+		if maxID, err := store.Max(key.Table(), "id"); err == nil {
+			Model.NextIDs[key] = &maxID
 		} else {
 			panic(err)
 		}
@@ -60,61 +58,87 @@ func fetchStatic() {
 		if !key.IsDB() {
 			continue
 		}
-		// select文組み立て
-		if rows, err := db.Table(key.Table()).Where("deleted_at is null").Rows(); err == nil {
-			for rows.Next() {
-				// 対応する Struct を作成
-				obj := key.Obj(Model).(entities.Persistable)
-				if err := db.ScanRows(rows, obj); err == nil {
-					obj.P().Reset()
+		// 対応する Struct を作成
+		alloc := func() interface{} {
+			return key.Obj(Model)
+		}
+		if err := store.Each(key.Table(), alloc, func(raw interface{}) {
+			obj := raw.(entities.Persistable)
+			obj.P().Reset()
 
-					// Model に登録
-					Model.Values[key].SetMapIndex(reflect.ValueOf(obj.B().Idx()), reflect.ValueOf(obj))
-					cnt++
-				} else {
-					panic(err)
-				}
-			}
-		} else {
+			// Model に登録
+			Model.Values[key].SetMapIndex(reflect.ValueOf(obj.B().Idx()), reflect.ValueOf(obj))
+			cnt++
+		}); err != nil {
 			panic(err)
 		}
 	}
 	log.Printf("restored %d entities", cnt)
 }
 
+// fetchScore selects snapshots within retention period for Restore()
+func fetchScore() {
+	limit := GameClock.Now().Add(-conf.Game.Service.Ranking.Retention.D)
+	if err := store.FindSince(&ScoreHistory, "time_stamp", limit); err != nil {
+		panic(err)
+	}
+	log.Printf("restored %d scores", len(ScoreHistory))
+}
+
+// fetchClock restores progress of GameClock for Restore()
+func fetchClock() {
+	if ticks, err := store.Max("clock_states", "ticks"); err == nil {
+		GameClock.Ticks = ticks
+	} else {
+		panic(err)
+	}
+}
+
 // resolveStatic set pointer from id for Restore()
-func resolveStatic() {
+// Entities are deployed over Chunk after all references are resolved
+// because position of Train depends on its LineTask.
+func resolveStatic(m *entities.Model) {
 	for _, key := range entities.TypeList {
 		if !key.IsDB() {
 			continue
 		}
-		Model.ForEach(key, func(obj entities.Entity) {
+		m.ForEach(key, func(obj entities.Entity) {
 			obj.(entities.Migratable).UnMarshal()
-			Model.RootCluster.Add(obj)
+		})
+	}
+	for _, key := range entities.TypeList {
+		if !key.IsDB() {
+			continue
+		}
+		m.ForEach(key, func(obj entities.Entity) {
+			m.RootCluster.Add(obj)
 		})
 	}
 }
 
 // genDynamics create Dynamic instances
-func genDynamics() {
-	for _, o := range Model.Players {
+func genDynamics(m *entities.Model) {
+	for _, o := range m.Players {
 		hash := auther.Digest(auther.Decrypt(o.LoginID))
-		Model.Logins[o.Auth][hash] = o
-		route.RefreshTracks(o, conf.Game.Service.Routing.Worker)
+		m.Logins[o.Auth][hash] = o
+		route.RefreshTracks(o)
 	}
-	for _, r := range Model.Residences {
+	for _, r := range m.Residences {
 		r.GenOutSteps()
 	}
-	for _, g := range Model.Gates {
+	for _, c := range m.Companies {
+		c.GenOutSteps()
+	}
+	for _, g := range m.Gates {
 		g.GenOutSteps()
 	}
-	for _, p := range Model.Platforms {
+	for _, p := range m.Platforms {
 		p.GenOutSteps()
 	}
-	for _, l := range Model.RailLines {
-		route.RefreshTransports(l, conf.Game.Service.Routing.Worker)
+	for _, l := range m.RailLines {
+		route.RefreshTransports(l)
 	}
-	for _, h := range Model.Humans {
+	for _, h := range m.Humans {
 		h.GenOutSteps()
 	}
 }
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/yasshi2525/RushHour/entities"
+	"github.com/yasshi2525/RushHour/route"
+)
+
+// RouteDump is route towards Company for diagnosis
+type RouteDump struct {
+	Tree *route.Dump `json:"tree"`
+	// Residences is id of Residence whose Human can't reach Company
+	Residences []uint `json:"unreachable_residences"`
+	// DOT is Tree in Graphviz DOT language
+	DOT string `json:"dot"`
+}
+
+// DumpRoute exports shortest path tree towards Company and Residences not connected to it.
+func DumpRoute(c *entities.Company) (*RouteDump, error) {
+	payload, ok := RouteTemplate[entities.COMPANY]
+	if !ok {
+		return nil, fmt.Errorf("route is not searched yet")
+	}
+	model, ok := payload.Route[c.ID]
+	if !ok {
+		return nil, fmt.Errorf("route to %s(%d) is not searched yet", entities.COMPANY, c.ID)
+	}
+	tree := route.NewDump(model, entities.COMPANY, c.ID)
+	res := &RouteDump{Tree: tree, Residences: []uint{}, DOT: tree.DOT()}
+	Model.ForEachSorted(entities.RESIDENCE, func(obj entities.Entity) {
+		if n, ok := model.Nodes[entities.RESIDENCE][obj.B().Idx()]; !ok || n.ViaEdge == nil {
+			res.Residences = append(res.Residences, obj.B().Idx())
+		}
+	})
+	return res, nil
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/yasshi2525/RushHour/auth"
+	"github.com/yasshi2525/RushHour/config"
+)
+
+func TestDumpRoute(t *testing.T) {
+	wd, _ := os.Getwd()
+	conf, _ = config.Load(fmt.Sprintf("%s/../config", wd))
+	conf.Game.Service.Routing.Worker = 1
+	auther, _ = auth.GetAuther(conf.Secret.Auth)
+	InitRepository()
+
+	r := Model.NewResidence(0, 0)
+	c := Model.NewCompany(100, 0)
+
+	if _, err := DumpRoute(c); err == nil {
+		t.Errorf("DumpRoute() before routing got nil, want error")
+	}
+	processRouting(context.Background())
+	applyRouting()
+
+	// Residence created after routing is not connected yet
+	isolated := Model.NewResidence(50, 50)
+	res, err := DumpRoute(c)
+	if err != nil {
+		t.Fatalf("DumpRoute() got %v, want nil", err)
+	}
+	if got := len(res.Tree.Nodes); got != 1 || res.Tree.Nodes[0].ID != r.ID {
+		t.Errorf("Tree.Nodes got %v, want [%v]", res.Tree.Nodes, r)
+	}
+	if got := res.Residences; len(got) != 1 || got[0] != isolated.ID {
+		t.Errorf("Residences got %v, want [%d]", got, isolated.ID)
+	}
+	if !strings.HasPrefix(res.DOT, "digraph") {
+		t.Errorf("DOT got %s, want digraph", res.DOT)
+	}
+}
@@ -14,13 +14,28 @@ var routingContext context.Context
 var routingCancel context.CancelFunc
 var routingBlockConunt int
 
+// routeGraph is Step and Transport used in last routing.
+// It is updated with RouteTemplate incrementally.
+var routeGraph *route.Model
+
 var searching bool
 
+// routingResult is route calculated in background, which waits for being applied to Model.
+type routingResult struct {
+	graph    *route.Model
+	payloads map[entities.ModelType]*route.Payload
+}
+
+// pendingRouting is routing result not applied yet. It is protected by MuModel.
+var pendingRouting *routingResult
+
 // IsSearching represents whether searching is executed or not.
 func IsSearching() bool {
 	return searching
 }
 
+// StartRouting rebuilds route from scratch in background.
+// Use UpdateRouting for reflecting an operation.
 func StartRouting() {
 	if routingCancel != nil {
 		routingCancel()
@@ -44,8 +59,10 @@ func processRouting(ctx context.Context) {
 	initialRouting := RouteTemplate == nil
 	alertEnabled := conf.Game.Service.Routing.Alert > 0
 
-	lock, template, ok := scan(ctx)
+	beginSearching()
+	lock, graph, templates, ok := scan(ctx)
 	if !ok {
+		endSearching()
 		routingBlockConunt++
 		if alertEnabled && routingBlockConunt >= conf.Game.Service.Routing.Alert {
 			log.Printf("routing was canceled (1/3) in scanning phase by %d times", routingBlockConunt)
@@ -53,8 +70,9 @@ func processRouting(ctx context.Context) {
 		return
 	}
 
-	payload, ok := search(ctx, template)
+	payloads, payload, ok := search(ctx, templates)
 	if !ok {
+		endSearching()
 		routingBlockConunt++
 		if alertEnabled && routingBlockConunt >= conf.Game.Service.Routing.Alert {
 			log.Printf("routing was canceled (2/3) in searching phase (%d/%d) by %d times",
@@ -63,8 +81,7 @@ func processRouting(ctx context.Context) {
 		return
 	}
 
-	RouteTemplate = payload
-	reflectModel()
+	queueRouting(graph, payloads)
 	if alertEnabled && routingBlockConunt >= conf.Game.Service.Routing.Alert {
 		log.Printf("routing was successfully ended after %d times blocking", routingBlockConunt)
 	}
@@ -76,26 +93,133 @@ func processRouting(ctx context.Context) {
 	}
 }
 
-func scan(ctx context.Context) (time.Time, *route.Model, bool) {
+func scan(ctx context.Context) (time.Time, *route.Model, map[entities.ModelType]*route.Model, bool) {
 	MuModel.RLock()
 	defer MuModel.RUnlock()
 
 	lock := time.Now()
-	model, ok := route.Scan(ctx, Model)
-	return lock, model, ok
+	graph, ok := route.Scan(ctx, Model, costModel())
+	templates := make(map[entities.ModelType]*route.Model)
+	for _, t := range route.GoalTypes {
+		templates[t] = graph.WithGoals(Model.Ids(t))
+	}
+	return lock, graph, templates, ok
 }
 
-func search(ctx context.Context, template *route.Model) (*route.Payload, bool) {
-	return route.Search(ctx, entities.COMPANY, conf.Game.Service.Routing.Worker, template)
+// beginSearching makes changes of Model kept until routing from scratch ends.
+// searching is changed with exclusive lock because readers of Model refer to it.
+// Result of previous routing not applied yet is discarded because new one includes it.
+func beginSearching() {
+	MuModel.Lock()
+	defer MuModel.Unlock()
+
+	searching = true
+	pendingRouting = nil
+	PublishView()
 }
 
-func reflectModel() {
+func endSearching() {
 	MuModel.Lock()
 	defer MuModel.Unlock()
 
-	for _, model := range RouteTemplate.Route {
-		for _, n := range model.Nodes[entities.HUMAN] {
-			Model.Humans[n.ID].Current = Model.Steps[n.ViaEdge.ID]
+	searching = false
+	PublishView()
+}
+
+// search calculates route for each type of goal.
+// When searching is canceled, it also returns payload of canceled goal type.
+func search(ctx context.Context, templates map[entities.ModelType]*route.Model) (map[entities.ModelType]*route.Payload, *route.Payload, bool) {
+	payloads := make(map[entities.ModelType]*route.Payload)
+	for _, t := range route.GoalTypes {
+		payload, ok := route.Search(ctx, t, route.Background, templates[t])
+		if !ok {
+			return payloads, payload, false
 		}
+		payloads[t] = payload
 	}
+	return payloads, nil, true
+}
+
+// queueRouting keeps result of routing until next tick boundary
+// so that route changes between steps of simulation, not when background routing ends.
+func queueRouting(graph *route.Model, payloads map[entities.ModelType]*route.Payload) {
+	MuModel.Lock()
+	defer MuModel.Unlock()
+
+	pendingRouting = &routingResult{graph, payloads}
+}
+
+// applyRouting replaces route with result of routing if it exists.
+// Changes while searching are applied to it in the same way as UpdateRouting.
+// It must be called with lock of MuModel at tick boundary.
+func applyRouting() {
+	if pendingRouting == nil {
+		return
+	}
+	muJourney.Lock()
+	searching = false
+	routeGraph = pendingRouting.graph
+	RouteTemplate = pendingRouting.payloads
+	pendingRouting = nil
+	route.Update(routeGraph, RouteTemplate, Model.TakeJournal())
+	indexJourney()
+	muJourney.Unlock()
+
+	rerouteHumans()
+}
+
+// UpdateRouting reflects changes of Model since last routing to route incrementally.
+// It must be called with lock of MuModel after an operation.
+// Changes are kept until routing from scratch ends if it is running.
+func UpdateRouting() {
+	if routeGraph == nil {
+		return
+	}
+	muJourney.Lock()
+	indexJourney()
+	var n int
+	if !searching {
+		if j := Model.TakeJournal(); !j.IsEmpty() {
+			n = route.Update(routeGraph, RouteTemplate, j)
+		}
+	}
+	muJourney.Unlock()
+
+	if n > 0 {
+		rerouteHumans()
+	}
+}
+
+// ReweightRouting recalculates cost of route with current congestion.
+// It must be called with lock of MuModel.
+func ReweightRouting() {
+	if routeGraph == nil || searching {
+		return
+	}
+	UpdateRouting()
+	muJourney.Lock()
+	n := route.Reweight(routeGraph, RouteTemplate, Model)
+	muJourney.Unlock()
+
+	if n > 0 {
+		rerouteHumans()
+	}
+}
+
+// costModel returns CostModel on configuration.
+func costModel() *route.CostModel {
+	c := conf.Game.Service.Routing.Congestion
+	if c.Interval.D == 0 {
+		return nil
+	}
+	return &route.CostModel{Crowd: c.Crowd, Full: c.Full, Wait: c.Wait}
+}
+
+// rerouteHumans makes Human walking or waiting follow current route.
+func rerouteHumans() {
+	Model.ForEachSorted(entities.HUMAN, func(obj entities.Entity) {
+		if h := obj.(*entities.Human); h.On == entities.OnGround || h.On == entities.OnPlatform {
+			routeHuman(h)
+		}
+	})
 }
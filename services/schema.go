@@ -0,0 +1,237 @@
+package services
+
+import (
+	"github.com/yasshi2525/RushHour/storage"
+)
+
+// Definitions in this file are frozen once released.
+// Change schema by adding new step to schemaSteps instead of editing existing ones
+// because databases which already applied the step never see the edit.
+//
+// POLICY:
+//   1. not allow nullable field because go cann't identify nil or zero value
+//      so, no foreign key restriction for nullable field
+//   2. id must be grater than 0. id: 0 means nil
+//   3. not use sql.NullInst64 for performance
+
+// schemaSteps is list of steps ordered by version.
+var schemaSteps = []schemaStep{
+	{
+		version: 1,
+		name:    "create tables",
+		tables: []storage.Table{
+			opLogTable,
+			entityTable("players", nil,
+				storage.Column{Name: "level", Type: storage.Uint, NotNull: true},
+				storage.Column{Name: "o_auth_display_name", Type: storage.Text, NotNull: true},
+				storage.Column{Name: "custom_display_name", Type: storage.Text, NotNull: true},
+				storage.Column{Name: "use_custom_display_name", Type: storage.Bool, NotNull: true},
+				storage.Column{Name: "o_auth_image", Type: storage.Text, NotNull: true},
+				storage.Column{Name: "custom_image", Type: storage.Text, NotNull: true},
+				storage.Column{Name: "use_custom_image", Type: storage.Bool, NotNull: true},
+				storage.Column{Name: "login_id", Type: storage.Text, NotNull: true},
+				storage.Column{Name: "password", Type: storage.Text, NotNull: true},
+				storage.Column{Name: "auth", Type: storage.Uint, NotNull: true, Index: true},
+				storage.Column{Name: "o_auth_token", Type: storage.Text, NotNull: true},
+				storage.Column{Name: "o_auth_secret", Type: storage.Text, NotNull: true},
+				storage.Column{Name: "hue", Type: storage.Int, NotNull: true}),
+			entityTable("residences", nil,
+				storage.Column{Name: "x", Type: storage.Float, Index: true},
+				storage.Column{Name: "y", Type: storage.Float, Index: true},
+				storage.Column{Name: "capacity", Type: storage.Int},
+				storage.Column{Name: "wait", Type: storage.Float},
+				storage.Column{Name: "name", Type: storage.String}),
+			entityTable("companies", nil,
+				storage.Column{Name: "x", Type: storage.Float, Index: true},
+				storage.Column{Name: "y", Type: storage.Float, Index: true},
+				storage.Column{Name: "attract", Type: storage.Float, NotNull: true},
+				storage.Column{Name: "name", Type: storage.String}),
+			entityTable("rail_nodes", nil,
+				storage.Column{Name: "x", Type: storage.Float, Index: true},
+				storage.Column{Name: "y", Type: storage.Float, Index: true}),
+			entityTable("rail_edges", []storage.ForeignKey{
+				// RailEdge connects RailNode
+				{Column: "from_id", Table: "rail_nodes", OnDelete: "CASCADE", OnUpdate: "RESTRICT"},
+				{Column: "to_id", Table: "rail_nodes", OnDelete: "CASCADE", OnUpdate: "RESTRICT"},
+			},
+				storage.Column{Name: "from_id", Type: storage.Uint, NotNull: true},
+				storage.Column{Name: "to_id", Type: storage.Uint, NotNull: true},
+				storage.Column{Name: "reverse_id", Type: storage.Uint, NotNull: true}),
+			entityTable("stations", nil,
+				storage.Column{Name: "name", Type: storage.String, NotNull: true}),
+			entityTable("gates", []storage.ForeignKey{
+				// Station composes Platforms and Gates
+				{Column: "station_id", Table: "stations", OnDelete: "CASCADE", OnUpdate: "RESTRICT"},
+			},
+				storage.Column{Name: "num", Type: storage.Int, NotNull: true},
+				storage.Column{Name: "mobility", Type: storage.Float, NotNull: true},
+				storage.Column{Name: "occupied", Type: storage.Int, NotNull: true},
+				storage.Column{Name: "station_id", Type: storage.Uint, NotNull: true}),
+			entityTable("platforms", []storage.ForeignKey{
+				{Column: "rail_node_id", Table: "rail_nodes", OnDelete: "RESTRICT", OnUpdate: "RESTRICT"},
+				{Column: "station_id", Table: "stations", OnDelete: "CASCADE", OnUpdate: "RESTRICT"},
+			},
+				storage.Column{Name: "capacity", Type: storage.Int, NotNull: true},
+				storage.Column{Name: "station_id", Type: storage.Uint},
+				storage.Column{Name: "rail_node_id", Type: storage.Uint}),
+			entityTable("rail_lines", nil,
+				storage.Column{Name: "name", Type: storage.String},
+				storage.Column{Name: "auto_ext", Type: storage.Bool},
+				storage.Column{Name: "auto_pass", Type: storage.Bool}),
+			entityTable("line_tasks", []storage.ForeignKey{
+				// Line composes LineTasks
+				{Column: "rail_line_id", Table: "rail_lines", OnDelete: "CASCADE", OnUpdate: "RESTRICT"},
+			},
+				storage.Column{Name: "task_type", Type: storage.Uint, NotNull: true},
+				storage.Column{Name: "rail_line_id", Type: storage.Uint, NotNull: true},
+				storage.Column{Name: "next_id", Type: storage.Uint},
+				storage.Column{Name: "stay_id", Type: storage.Uint},
+				storage.Column{Name: "moving_id", Type: storage.Uint}),
+			entityTable("trains", nil,
+				storage.Column{Name: "x", Type: storage.Float, Index: true},
+				storage.Column{Name: "y", Type: storage.Float, Index: true},
+				storage.Column{Name: "capacity", Type: storage.Int},
+				storage.Column{Name: "mobility", Type: storage.Int},
+				storage.Column{Name: "speed", Type: storage.Float},
+				storage.Column{Name: "progress", Type: storage.Float},
+				storage.Column{Name: "name", Type: storage.String, NotNull: true},
+				storage.Column{Name: "task_id", Type: storage.Uint}),
+			entityTable("humen", []storage.ForeignKey{
+				// Human departs from Residence and destinates to Company
+				{Column: "from_id", Table: "residences", OnDelete: "RESTRICT", OnUpdate: "RESTRICT"},
+				{Column: "to_id", Table: "companies", OnDelete: "RESTRICT", OnUpdate: "RESTRICT"},
+			},
+				storage.Column{Name: "x", Type: storage.Float, Index: true},
+				storage.Column{Name: "y", Type: storage.Float, Index: true},
+				storage.Column{Name: "available", Type: storage.Float, NotNull: true},
+				storage.Column{Name: "mobility", Type: storage.Float, NotNull: true},
+				storage.Column{Name: "angle", Type: storage.Float, NotNull: true},
+				storage.Column{Name: "lifespan", Type: storage.Float, NotNull: true},
+				storage.Column{Name: "progress", Type: storage.Float, NotNull: true},
+				storage.Column{Name: "from_id", Type: storage.Uint, NotNull: true},
+				storage.Column{Name: "to_id", Type: storage.Uint, NotNull: true},
+				storage.Column{Name: "platform_id", Type: storage.Uint},
+				storage.Column{Name: "train_id", Type: storage.Uint}),
+		},
+	},
+	{
+		version: 2,
+		name:    "add player money",
+		columns: []schemaColumn{
+			{"players", storage.Column{Name: "money", Type: storage.BigInt, NotNull: true, Default: "0"}},
+		},
+		// existing players start with initial money as new ones do
+		fill: func(tx storage.Tx) error {
+			return tx.Fill("players", "money", conf.Game.Economy.Initial)
+		},
+	},
+	{
+		version: 3,
+		name:    "add player statistics",
+		columns: []schemaColumn{
+			{"players", storage.Column{Name: "delivered", Type: storage.BigInt, NotNull: true, Default: "0"}},
+			{"players", storage.Column{Name: "commuters", Type: storage.BigInt, NotNull: true, Default: "0"}},
+			{"players", storage.Column{Name: "commute_time", Type: storage.Float, NotNull: true, Default: "0"}},
+			{"players", storage.Column{Name: "revenue", Type: storage.BigInt, NotNull: true, Default: "0"}},
+		},
+		tables: []storage.Table{
+			{
+				Name: "scores",
+				Columns: []storage.Column{
+					{Name: "id", Type: storage.ID},
+					{Name: "created_at", Type: storage.Time},
+					{Name: "updated_at", Type: storage.Time},
+					{Name: "deleted_at", Type: storage.Time, Index: true},
+					{Name: "owner_id", Type: storage.Uint, NotNull: true, Index: true},
+					{Name: "delivered", Type: storage.BigInt, NotNull: true},
+					{Name: "commuters", Type: storage.BigInt, NotNull: true},
+					{Name: "commute_time", Type: storage.Float, NotNull: true},
+					{Name: "revenue", Type: storage.BigInt, NotNull: true},
+					{Name: "network", Type: storage.Float, NotNull: true},
+					{Name: "time_stamp", Type: storage.Time, NotNull: true, Index: true},
+				},
+			},
+		},
+	},
+	{
+		version: 4,
+		name:    "add demand multiplier",
+		columns: []schemaColumn{
+			{"residences", storage.Column{Name: "demand", Type: storage.Float, NotNull: true, Default: "1"}},
+			{"companies", storage.Column{Name: "demand", Type: storage.Float, NotNull: true, Default: "1"}},
+		},
+	},
+	{
+		version: 5,
+		name:    "add return commute",
+		columns: []schemaColumn{
+			{"humen", storage.Column{Name: "work", Type: storage.Float, NotNull: true, Default: "0"}},
+			{"humen", storage.Column{Name: "returning", Type: storage.Bool, NotNull: true, Default: "0"}},
+		},
+	},
+	{
+		version: 6,
+		name:    "add game clock",
+		tables: []storage.Table{
+			{
+				Name: "clock_states",
+				Columns: []storage.Column{
+					{Name: "id", Type: storage.ID},
+					{Name: "ticks", Type: storage.BigInt, NotNull: true},
+				},
+			},
+		},
+	},
+	{
+		version: 7,
+		name:    "stamp scores by game clock",
+		// scores stamped by wall clock can't be compared with GameClock
+		fill: func(tx storage.Tx) error {
+			return tx.DeleteAll("scores")
+		},
+	},
+}
+
+// schemaVersionTable records applied steps. It is created before any step.
+var schemaVersionTable = storage.Table{
+	Name: "schema_version",
+	Columns: []storage.Column{
+		{Name: "version", Type: storage.Uint, NotNull: true},
+		{Name: "name", Type: storage.String, NotNull: true},
+		{Name: "applied_at", Type: storage.Time, NotNull: true},
+	},
+	PrimaryKey: "version",
+}
+
+// opLogTable stores OpLog.
+var opLogTable = storage.Table{
+	Name: "op_logs",
+	Columns: []storage.Column{
+		{Name: "id", Type: storage.ID},
+		{Name: "created_at", Type: storage.Time},
+		{Name: "updated_at", Type: storage.Time},
+		{Name: "deleted_at", Type: storage.Time, Index: true},
+		{Name: "op", Type: storage.String},
+		{Name: "owner_id", Type: storage.Uint},
+		{Name: "obj1", Type: storage.String},
+		{Name: "obj2", Type: storage.String},
+		{Name: "obj3", Type: storage.String},
+		{Name: "obj4", Type: storage.String},
+		{Name: "time_stamp", Type: storage.Time},
+	},
+}
+
+// entityTable returns table of entities having columns of Base and Persistence.
+func entityTable(name string, fks []storage.ForeignKey, columns ...storage.Column) storage.Table {
+	return storage.Table{
+		Name: name,
+		Columns: append([]storage.Column{
+			{Name: "id", Type: storage.ID},
+			{Name: "owner_id", Type: storage.Uint},
+			{Name: "created_at", Type: storage.Time},
+			{Name: "updated_at", Type: storage.Time},
+			{Name: "deleted_at", Type: storage.Time, Index: true},
+		}, columns...),
+		ForeignKeys: fks,
+	}
+}
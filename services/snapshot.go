@@ -0,0 +1,319 @@
+package services
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"reflect"
+	"time"
+
+	"github.com/yasshi2525/RushHour/entities"
+)
+
+// SnapshotVersion is version of snapshot format.
+// Increment it when layout of Snapshot or persisted fields of entities change.
+const SnapshotVersion = 1
+
+// Snapshot is whole world independent from database.
+// It is saved as gzipped JSON.
+// Players keep encrypted login id, so server loading it must have the same auth key.
+type Snapshot struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	// Ticks is the number of steps since game began
+	Ticks uint64 `json:"ticks"`
+	// NextIDs is last id of each type
+	NextIDs map[string]uint64 `json:"next_ids"`
+	// Records is persisted fields of entities for each type.
+	// Fields are same as columns of database, which include position of Human and progress of Train.
+	Records map[string][]map[string]json.RawMessage `json:"records"`
+}
+
+// SaveSnapshot writes whole world to w.
+func SaveSnapshot(w io.Writer, withLock bool) error {
+	start := time.Now()
+	if withLock {
+		MuModel.RLock()
+	}
+	snap, err := captureSnapshot()
+	if withLock {
+		MuModel.RUnlock()
+	}
+	if err != nil {
+		return err
+	}
+
+	gw := gzip.NewWriter(w)
+	if err := json.NewEncoder(gw).Encode(snap); err != nil {
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		return err
+	}
+	log.Printf("snapshot was successfully saved in %.2f sec", time.Since(start).Seconds())
+	return nil
+}
+
+// LoadSnapshot replaces whole world with snapshot read from r.
+// New world is built aside and current world is kept when snapshot is invalid.
+// When backup is enabled, database is overwritten by snapshot in one transaction
+// and world is replaced only after it succeeds.
+func LoadSnapshot(r io.Reader, withLock bool) error {
+	if IsInOperation() {
+		return fmt.Errorf("couldn't load snapshot during under operation")
+	}
+	start := time.Now()
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+	snap := &Snapshot{}
+	if err := json.NewDecoder(gr).Decode(snap); err != nil {
+		return err
+	}
+	if snap.Version != SnapshotVersion {
+		return fmt.Errorf("unsupported snapshot version %d (want %d)", snap.Version, SnapshotVersion)
+	}
+
+	if withLock {
+		MuModel.Lock()
+		defer MuModel.Unlock()
+	}
+	m := entities.NewModel(conf.Game.Entity, auther)
+	objs, err := decodeSnapshot(m, snap)
+	if err != nil {
+		return err
+	}
+	for _, key := range entities.TypeList {
+		if !key.IsDB() {
+			continue
+		}
+		id := snap.NextIDs[key.String()]
+		m.NextIDs[key] = &id
+		for _, obj := range objs[key] {
+			m.Values[key].SetMapIndex(reflect.ValueOf(obj.B().Idx()), reflect.ValueOf(obj))
+		}
+	}
+	resolveStatic(m)
+	genDynamics(m)
+
+	if conf.Game.Service.Backup.Enabled {
+		if err := overwriteDB(m, snap.Ticks); err != nil {
+			return err
+		}
+	}
+
+	InitRepository()
+	Model = m
+	GameClock.Ticks = snap.Ticks
+	CreateIfAdmin()
+	StartRouting()
+	log.Printf("snapshot was successfully loaded in %.2f sec", time.Since(start).Seconds())
+	return nil
+}
+
+// overwriteDB replaces all records of database with entities of m and ticks in one transaction.
+// Entities are regarded as persisted after it succeeds.
+func overwriteDB(m *entities.Model, ticks uint64) error {
+	b := newBackupBatch()
+	b.clock = &ClockState{ID: 1, Ticks: ticks}
+	for _, key := range entities.TypeList {
+		if !key.IsDB() {
+			continue
+		}
+		m.ForEach(key, func(obj entities.Entity) {
+			b.records[key][obj.B().ID] = copyEntity(obj)
+		})
+	}
+
+	muBackupWriter.Lock()
+	defer muBackupWriter.Unlock()
+	tx, err := store.Begin()
+	if err != nil {
+		return err
+	}
+	if err := purgeTables(tx, nil); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := persistStatic(tx, b); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := persistClock(tx, b); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	for _, key := range entities.TypeList {
+		if !key.IsDB() {
+			continue
+		}
+		m.ForEach(key, func(obj entities.Entity) {
+			obj.(entities.Persistable).P().Reset()
+		})
+	}
+	return nil
+}
+
+// captureSnapshot copies persisted fields of all entities.
+func captureSnapshot() (*Snapshot, error) {
+	snap := &Snapshot{
+		Version:   SnapshotVersion,
+		CreatedAt: time.Now(),
+		Ticks:     GameClock.Ticks,
+		NextIDs:   make(map[string]uint64),
+		Records:   make(map[string][]map[string]json.RawMessage),
+	}
+	for _, key := range entities.TypeList {
+		if !key.IsDB() {
+			continue
+		}
+		snap.NextIDs[key.String()] = *Model.NextIDs[key]
+		records := []map[string]json.RawMessage{}
+		var err error
+		Model.ForEach(key, func(obj entities.Entity) {
+			if err != nil {
+				return
+			}
+			rec := make(map[string]json.RawMessage)
+			eachPersistedField(reflect.ValueOf(obj).Elem(), func(name string, f reflect.Value) {
+				if err != nil {
+					return
+				}
+				rec[name], err = json.Marshal(primitive(f))
+			})
+			records = append(records, rec)
+		})
+		if err != nil {
+			return nil, err
+		}
+		snap.Records[key.String()] = records
+	}
+	return snap, nil
+}
+
+// decodeSnapshot creates entities from records of snapshot.
+// They are not registered to m yet.
+func decodeSnapshot(m *entities.Model, snap *Snapshot) (map[entities.ModelType][]entities.Entity, error) {
+	objs := make(map[entities.ModelType][]entities.Entity)
+	known := make(map[string]bool)
+	for _, key := range entities.TypeList {
+		if !key.IsDB() {
+			continue
+		}
+		known[key.String()] = true
+		for _, rec := range snap.Records[key.String()] {
+			obj := key.Obj(m)
+			var err error
+			eachPersistedField(reflect.ValueOf(obj).Elem(), func(name string, f reflect.Value) {
+				if raw, ok := rec[name]; ok && err == nil {
+					if e := setPrimitive(f, raw); e != nil {
+						err = fmt.Errorf("%s.%s: %v", key, name, e)
+					}
+				}
+			})
+			if err != nil {
+				return nil, err
+			}
+			if obj.B().ID == ZERO {
+				return nil, fmt.Errorf("%s has no id", key)
+			}
+			objs[key] = append(objs[key], obj)
+		}
+	}
+	for name := range snap.Records {
+		if !known[name] {
+			return nil, fmt.Errorf("unknown type %s", name)
+		}
+	}
+	return objs, nil
+}
+
+// eachPersistedField calls fn for each field which gorm stores in database.
+// Embedded structs such as Base and Point are expanded.
+func eachPersistedField(v reflect.Value, fn func(name string, f reflect.Value)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" || sf.Tag.Get("gorm") == "-" {
+			continue
+		}
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			eachPersistedField(v.Field(i), fn)
+			continue
+		}
+		switch sf.Type.Kind() {
+		case reflect.Map, reflect.Slice, reflect.Interface, reflect.Func, reflect.Chan:
+			continue
+		case reflect.Ptr, reflect.Struct:
+			if sf.Type != reflect.TypeOf(time.Time{}) && sf.Type != reflect.TypeOf(&time.Time{}) {
+				continue
+			}
+		}
+		fn(sf.Name, v.Field(i))
+	}
+}
+
+// primitive returns value of field without custom JSON format such as AuthType.
+func primitive(f reflect.Value) interface{} {
+	switch f.Kind() {
+	case reflect.Bool:
+		return f.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return f.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return f.Uint()
+	case reflect.Float32, reflect.Float64:
+		return f.Float()
+	case reflect.String:
+		return f.String()
+	default:
+		// time.Time or *time.Time
+		return f.Interface()
+	}
+}
+
+// setPrimitive sets value encoded by primitive.
+func setPrimitive(f reflect.Value, raw json.RawMessage) error {
+	switch f.Kind() {
+	case reflect.Bool:
+		var v bool
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return err
+		}
+		f.SetBool(v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var v int64
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return err
+		}
+		f.SetInt(v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var v uint64
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return err
+		}
+		f.SetUint(v)
+	case reflect.Float32, reflect.Float64:
+		var v float64
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return err
+		}
+		f.SetFloat(v)
+	case reflect.String:
+		var v string
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return err
+		}
+		f.SetString(v)
+	default:
+		return json.Unmarshal(raw, f.Addr().Interface())
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/yasshi2525/RushHour/auth"
+	"github.com/yasshi2525/RushHour/config"
+	"github.com/yasshi2525/RushHour/entities"
+)
+
+func TestSnapshot(t *testing.T) {
+	wd, _ := os.Getwd()
+	conf, _ = config.Load(fmt.Sprintf("%s/../config", wd))
+	conf.Game.Service.Routing.Worker = 1
+	conf.Game.Service.Backup.Enabled = false
+	conf.Game.Economy.Initial = 1000000
+	auther, _ = auth.GetAuther(conf.Secret.Auth)
+	InitLock()
+	InitRepository()
+	isInOperation = false
+
+	MuModel.Lock()
+	admin, _ := CreatePlayer("admin", "admin", "admin", 0, entities.Admin)
+	o, _ := CreatePlayer("test", "test", "test", 0, entities.Normal)
+	CreateRailNode(o, 0, 0, 0)
+	var rn *entities.RailNode
+	for _, x := range Model.RailNodes {
+		rn = x
+	}
+	ExtendRailNode(o, rn, 10, 0, 0)
+	r, _ := CreateResidence(admin, 0, 0)
+	c, _ := CreateCompany(admin, 10, 10)
+	h := Model.NewHuman(r, c)
+	h.X, h.Y, h.Progress = 3, 4, 0.5
+	GameClock.Ticks = 100
+	MuModel.Unlock()
+	waitRefresh(t)
+
+	var buf bytes.Buffer
+	if err := SaveSnapshot(&buf, true); err != nil {
+		t.Fatalf("SaveSnapshot() got %v, want nil", err)
+	}
+	data := buf.Bytes()
+
+	MuModel.Lock()
+	CreateResidence(admin, 5, 5)
+	MuModel.Unlock()
+
+	if err := LoadSnapshot(bytes.NewReader(data), true); err != nil {
+		t.Fatalf("LoadSnapshot() got %v, want nil", err)
+	}
+	waitRouting(t)
+
+	MuModel.RLock()
+	defer MuModel.RUnlock()
+	if got := GameClock.Ticks; got != 100 {
+		t.Errorf("Ticks got %d, want 100", got)
+	}
+	if got := len(Model.Residences); got != 1 {
+		t.Errorf("len(Residences) got %d, want 1", got)
+	}
+	if got := len(Model.RailEdges); got != 2 {
+		t.Errorf("len(RailEdges) got %d, want 2", got)
+	}
+	if x := Model.Players[o.ID]; x == nil || x.LoginID != o.LoginID || x.Money != o.Money {
+		t.Errorf("Players[%d] got %v, want %v", o.ID, x, o)
+	}
+	x := Model.Humans[h.ID]
+	if x == nil {
+		t.Fatalf("Humans[%d] got nil, want restored", h.ID)
+	}
+	if x.X != 3 || x.Y != 4 || x.Progress != 0.5 {
+		t.Errorf("Human got (%f, %f, %f), want (3, 4, 0.5)", x.X, x.Y, x.Progress)
+	}
+	if x.From != Model.Residences[r.ID] || x.To != Model.Companies[c.ID] {
+		t.Errorf("Human got from %v to %v, want resolved", x.From, x.To)
+	}
+	if got, want := Model.GenID(entities.HUMAN), h.ID+1; got != want {
+		t.Errorf("GenID() got %d, want %d", got, want)
+	}
+}
+
+func TestLoadSnapshotDB(t *testing.T) {
+	wd, _ := os.Getwd()
+	conf, _ = config.Load(fmt.Sprintf("%s/../config", wd))
+	conf.Game.Service.Routing.Worker = 1
+	conf.Game.Service.Backup.Enabled = true
+	conf.Game.Economy.Initial = 1000000
+	auther, _ = auth.GetAuther(conf.Secret.Auth)
+	InitLock()
+	InitRepository()
+	isInOperation = false
+	defer openTestDB(t)()
+
+	MuModel.Lock()
+	admin, _ := CreatePlayer("admin", "admin", "admin", 0, entities.Admin)
+	CreateResidence(admin, 0, 0)
+	MuModel.Unlock()
+	waitRefresh(t)
+
+	var buf bytes.Buffer
+	if err := SaveSnapshot(&buf, true); err != nil {
+		t.Fatalf("SaveSnapshot() got %v, want nil", err)
+	}
+	data := buf.Bytes()
+
+	MuModel.Lock()
+	CreateRailNode(admin, 0, 0, 0)
+	MuModel.Unlock()
+	Backup(true)
+
+	// writing residences fails after all tables are purged
+	residences := entities.RESIDENCE.Table()
+	if err := store.DropColumn(schemaAt(3)[residences], "demand"); err != nil {
+		t.Fatalf("DropColumn() got %v", err)
+	}
+	if err := LoadSnapshot(bytes.NewReader(data), true); err == nil {
+		t.Fatalf("LoadSnapshot() without %s.demand got nil, want error", residences)
+	}
+	MuModel.RLock()
+	if got := len(Model.RailNodes); got != 1 {
+		t.Errorf("len(RailNodes) got %d, want 1 kept in world", got)
+	}
+	MuModel.RUnlock()
+	if got, _ := store.Max(entities.RAILNODE.Table(), "id"); got == 0 {
+		t.Errorf("%s got empty, want kept in database", entities.RAILNODE.Table())
+	}
+	demand, _ := schemaAt(LatestSchema())[residences].Column("demand")
+	store.AddColumn(residences, demand)
+
+	if err := LoadSnapshot(bytes.NewReader(data), true); err != nil {
+		t.Fatalf("LoadSnapshot() got %v, want nil", err)
+	}
+	waitRouting(t)
+
+	InitRepository()
+	Restore(true)
+	if got := len(Model.RailNodes); got != 0 {
+		t.Errorf("len(RailNodes) got %d, want 0 in database", got)
+	}
+	if got := len(Model.Residences); got != 1 {
+		t.Errorf("len(Residences) got %d, want 1 in database", got)
+	}
+}
+
+func TestLoadSnapshotVersion(t *testing.T) {
+	isInOperation = false
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	json.NewEncoder(gw).Encode(&Snapshot{Version: SnapshotVersion + 1})
+	gw.Close()
+	if err := LoadSnapshot(&buf, true); err == nil {
+		t.Errorf("LoadSnapshot() got nil, want error for unsupported version")
+	}
+}
+
+// waitRouting blocks until routing from scratch ends and applies its result.
+func waitRouting(t *testing.T) {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		MuModel.Lock()
+		applyRouting()
+		done := RouteTemplate != nil
+		MuModel.Unlock()
+		if done {
+			// processRouting holds MuRoute until it returns
+			MuRoute.Lock()
+			MuRoute.Unlock()
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("routing didn't end")
+}
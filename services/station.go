@@ -4,7 +4,6 @@ import (
 	"fmt"
 
 	"github.com/yasshi2525/RushHour/entities"
-	"github.com/yasshi2525/RushHour/route"
 )
 
 //CreateStation create Station
@@ -15,13 +14,17 @@ func CreateStation(o *entities.Player, rn *entities.RailNode, name string) (*ent
 	if rn.OverPlatform != nil {
 		return nil, fmt.Errorf("staiton already exists")
 	}
+	if err := o.CanPay(conf.Game.Economy.Station); err != nil {
+		return nil, err
+	}
 
 	st := Model.NewStation(o)
 	g := Model.NewGate(st)
 	p := Model.NewPlatform(rn, g)
 
 	st.Name = name
-	StartRouting()
+	o.Pay(conf.Game.Economy.Station)
+	UpdateRouting()
 	AddOpLog("CreateStation", o, rn, st, g, p)
 	return st, nil
 }
@@ -32,15 +35,8 @@ func RemoveStation(o *entities.Player, id uint) error {
 		return err
 	} else {
 		st := st.(*entities.Station)
-		if o.ReRouting {
-			route.RefreshTracks(o, conf.Game.Service.Routing.Worker)
-		}
-		for _, l := range o.RailLines {
-			if l.ReRouting {
-				route.RefreshTransports(l, conf.Game.Service.Routing.Worker)
-			}
-		}
-		StartRouting()
+		requestRefresh(o)
+		UpdateRouting()
 		AddOpLog("RemoveStation", o, st)
 		return nil
 	}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"encoding/json"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/yasshi2525/RushHour/entities"
+)
+
+// MapEvent represents creation, update or deletion of object in viewport
+type MapEvent struct {
+	Op   string          `json:"op"`
+	Type string          `json:"type"`
+	ID   uint            `json:"id"`
+	Obj  json.RawMessage `json:"obj,omitempty"`
+}
+
+// MapEvents is the list of MapEvent pushed at once.
+// It is shared by Subscribers of the same viewport, so it must not be modified.
+type MapEvents struct {
+	Events    []*MapEvent `json:"events"`
+	Timestamp int64       `json:"timestamp"`
+}
+
+// Subscriber receives MapEvents in its viewport after each game procedure
+type Subscriber struct {
+	// C is closed when Subscriber is unsubscribed or too slow to receive
+	C chan *MapEvents
+
+	vp     *viewport
+	closed bool
+}
+
+// viewportKey identifies area of map and level of delegation.
+type viewportKey struct {
+	x, y, scale, delegate int
+}
+
+// viewport is area of map watched by Subscribers.
+// Events of it are built once per procedure and shared by its Subscribers.
+type viewport struct {
+	viewportKey
+	subs map[*Subscriber]bool
+	// known is id of objects pushed and not removed yet
+	known map[entities.ModelType]map[uint]bool
+	// model is Model the viewport watches. Whole map is compared when Model is replaced.
+	model *entities.Model
+	// since is when events were built last
+	since time.Time
+}
+
+// streamTypes is the list of resources pushed to Subscriber
+var streamTypes = []entities.ModelType{
+	entities.RESIDENCE,
+	entities.COMPANY,
+	entities.RAILNODE,
+	entities.RAILEDGE,
+	entities.PLATFORM,
+	entities.TRAIN,
+	entities.LINETASK,
+}
+
+var viewports = make(map[viewportKey]*viewport)
+
+// muStream is mutex lock for viewports. It must be locked after MuModel.
+var muStream sync.Mutex
+
+// Subscribe registers viewport and returns Subscriber whose channel has already had whole objects in viewport.
+// Objects are read from published View, so it doesn't need lock of MuModel.
+func Subscribe(x int, y int, scale int, delegate int) *Subscriber {
+	v := CurrentView()
+	key := viewportKey{x, y, scale, delegate}
+	sub := &Subscriber{
+		// +1 reserves room for first message
+		C: make(chan *MapEvents, conf.Game.Service.Procedure.Queue+1),
+	}
+	// first message is sent even if there is no object in viewport
+	first := newViewport(key, v)
+	sub.C <- first.events(first.viewMap(v, time.Time{}))
+
+	muStream.Lock()
+	defer muStream.Unlock()
+	if vp, ok := viewports[key]; ok {
+		sub.vp = vp
+	} else {
+		sub.vp = first
+		viewports[key] = first
+	}
+	sub.vp.subs[sub] = true
+	return sub
+}
+
+// Unsubscribe stops pushing events to Subscriber
+func Unsubscribe(sub *Subscriber) {
+	muStream.Lock()
+	defer muStream.Unlock()
+	sub.close()
+}
+
+// broadcastMap pushes difference of each viewport from previously published View.
+func broadcastMap() {
+	v := CurrentView()
+	muStream.Lock()
+	defer muStream.Unlock()
+	for _, vp := range viewports {
+		msg := vp.diff(v)
+		if len(msg.Events) == 0 {
+			continue
+		}
+		for sub := range vp.subs {
+			select {
+			case sub.C <- msg:
+			default:
+				log.Printf("subscriber was dropped because of out of queue")
+				sub.close()
+			}
+		}
+	}
+}
+
+func (sub *Subscriber) close() {
+	if !sub.closed {
+		sub.closed = true
+		close(sub.C)
+	}
+	if vp := sub.vp; vp != nil {
+		delete(vp.subs, sub)
+		if len(vp.subs) == 0 && viewports[vp.viewportKey] == vp {
+			delete(viewports, vp.viewportKey)
+		}
+	}
+}
+
+func newViewport(key viewportKey, v *View) *viewport {
+	vp := &viewport{
+		viewportKey: key,
+		subs:        make(map[*Subscriber]bool),
+		known:       make(map[entities.ModelType]map[uint]bool),
+		model:       v.model,
+		since:       v.PublishedAt,
+	}
+	for _, res := range streamTypes {
+		vp.known[res] = make(map[uint]bool)
+	}
+	return vp
+}
+
+// diff returns events changed in View after View of previous call.
+// Whole map is compared when Model is replaced or removal since previous call has been already pruned.
+func (vp *viewport) diff(v *View) *MapEvents {
+	defer func() { vp.since = v.PublishedAt }()
+
+	if vp.model != v.model {
+		vp.model = v.model
+		return vp.events(vp.viewMap(v, time.Time{}))
+	}
+	return vp.events(vp.viewMap(v, vp.since))
+}
+
+// viewMap returns delegates in viewport of View changed at since or later.
+func (vp *viewport) viewMap(v *View, since time.Time) *entities.MapData {
+	return v.Map.ViewMap(&entities.ChunkPoint{X: vp.x, Y: vp.y, Scale: vp.scale}, vp.delegate, since)
+}
+
+// events lists delegates in md and id of removed ones.
+// When md is whole map, objects not in it are regarded as removed.
+func (vp *viewport) events(md *entities.MapData) *MapEvents {
+	whole := !md.Delta
+	msg := &MapEvents{Events: []*MapEvent{}, Timestamp: md.Timestamp}
+	for _, key := range streamTypes {
+		known := vp.known[key]
+		found := make(map[uint]bool)
+		start := len(msg.Events)
+		for id, data := range md.Delegates[key] {
+			op := "update"
+			if !known[id] {
+				op = "create"
+			}
+			msg.Events = append(msg.Events, &MapEvent{op, key.API(), id, data})
+			known[id] = true
+			found[id] = true
+		}
+		upserts := msg.Events[start:]
+		sort.Slice(upserts, func(i, j int) bool { return upserts[i].ID < upserts[j].ID })
+
+		removed := md.Deletes[key.API()]
+		if whole {
+			removed = []uint{}
+			for id := range known {
+				removed = append(removed, id)
+			}
+		}
+		sort.Slice(removed, func(i, j int) bool { return removed[i] < removed[j] })
+		for _, id := range removed {
+			if known[id] && !found[id] {
+				msg.Events = append(msg.Events, &MapEvent{Op: "delete", Type: key.API(), ID: id})
+				delete(known, id)
+			}
+		}
+	}
+	return msg
+}
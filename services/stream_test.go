@@ -0,0 +1,80 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/yasshi2525/RushHour/auth"
+	"github.com/yasshi2525/RushHour/config"
+	"github.com/yasshi2525/RushHour/entities"
+)
+
+func TestSubscribe(t *testing.T) {
+	wd, _ := os.Getwd()
+	conf, _ = config.Load(fmt.Sprintf("%s/../config", wd))
+	conf.Game.Service.Routing.Worker = 1
+	conf.Game.Entity.MinScale = 0
+	conf.Game.Entity.MaxScale = 2
+	auther, _ = auth.GetAuther(conf.Secret.Auth)
+
+	InitRepository()
+	isInOperation = true
+
+	admin, _ := CreatePlayer("test", "test", "test", 0, entities.Admin)
+	CreateResidence(admin, 1, 1)
+
+	sub := Subscribe(0, 0, conf.Game.Entity.MaxScale, 0)
+	defer Unsubscribe(sub)
+
+	msg := <-sub.C
+	if got := len(msg.Events); got != 1 || msg.Events[0].Op != "create" || msg.Events[0].Type != "residences" {
+		t.Errorf("initial events should be create residence, but %+v", msg.Events)
+	}
+
+	publishGame()
+	if got := len(sub.C); got != 0 {
+		t.Errorf("no event should be pushed when nothing changes, but %d", got)
+	}
+
+	// subscribers of the same viewport share events
+	other := Subscribe(0, 0, conf.Game.Entity.MaxScale, 0)
+	<-other.C
+
+	c, _ := CreateCompany(admin, 2, 2)
+	publishGame()
+	msg = <-sub.C
+	if got := len(msg.Events); got != 1 || msg.Events[0].Op != "create" || msg.Events[0].Type != "companies" {
+		t.Errorf("create company should be pushed, but %+v", msg.Events)
+	}
+	if got := <-other.C; got != msg {
+		t.Errorf("events should be shared in viewport, but %+v and %+v", got, msg)
+	}
+	if got := len(viewports); got != 1 {
+		t.Errorf("viewports should be 1, but %d", got)
+	}
+	Unsubscribe(other)
+
+	RemoveCompany(admin, c.ID)
+	publishGame()
+	msg = <-sub.C
+	if got := len(msg.Events); got != 1 || msg.Events[0].Op != "delete" || msg.Events[0].Obj != nil {
+		t.Errorf("delete company should be pushed, but %+v", msg.Events)
+	}
+
+	// whole map is compared after Model is replaced
+	InitRepository()
+	publishGame()
+	msg = <-sub.C
+	if got := len(msg.Events); got != 1 || msg.Events[0].Op != "delete" || msg.Events[0].Type != "residences" {
+		t.Errorf("delete residence should be pushed after reset, but %+v", msg.Events)
+	}
+
+	Unsubscribe(sub)
+	if _, ok := <-sub.C; ok {
+		t.Errorf("channel should be closed after Unsubscribe")
+	}
+	if got := len(viewports); got != 0 {
+		t.Errorf("viewport should be removed after all subscribers leave, but %d", got)
+	}
+}
@@ -3,13 +3,15 @@ package services
 import (
 	"fmt"
 
-	"github.com/yasshi2525/RushHour/route"
-
 	"github.com/yasshi2525/RushHour/entities"
 )
 
 func CreateTrain(o *entities.Player, name string) (*entities.Train, error) {
+	if err := o.CanPay(conf.Game.Economy.Train); err != nil {
+		return nil, err
+	}
 	t := Model.NewTrain(o, name)
+	o.Pay(conf.Game.Economy.Train)
 	AddOpLog("CreateTrain", o, t)
 	return t, nil
 }
@@ -30,7 +32,9 @@ func DeployTrain(o *entities.Player, t *entities.Train, l *entities.RailLine) er
 		break
 	}
 	t.SetTask(start)
-	route.RefreshTransports(l, conf.Game.Service.Routing.Worker)
+	l.ReRouting = true
+	requestRefresh(o)
+	UpdateRouting()
 	AddOpLog("DeployTrain", o, t, start)
 	return nil
 }
@@ -42,7 +46,10 @@ func UnDeployTrain(o *entities.Player, t *entities.Train) error {
 	if lt := t.Task(); lt != nil {
 		t.UnLoad()
 		t.SetTask(nil)
-		route.RefreshTransports(lt.RailLine, conf.Game.Service.Routing.Worker)
+		lt.RailLine.ReRouting = true
+		requestRefresh(o)
+		UpdateRouting()
+		AddOpLog("UnDeployTrain", o, t, lt)
 	}
 	return nil
 }
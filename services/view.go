@@ -0,0 +1,104 @@
+package services
+
+import (
+	"encoding/json"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/yasshi2525/RushHour/entities"
+)
+
+// View is read-only copy of Model for requests which only read it.
+// It is published after Model changes, so readers refer to it without lock of MuModel
+// and aren't blocked by game procedure or operations.
+// It must not be modified after published.
+type View struct {
+	// Players is serialized Players of Model
+	Players json.RawMessage
+	// Clock is simulated time when it is published
+	Clock *ClockStatus
+	// Routing is progress of routing when it is published
+	Routing *RoutingStatus
+	// Map is copy of Cluster tree for viewing game map
+	Map *entities.MapView
+	// PublishedAt is when View is published
+	PublishedAt time.Time
+
+	// model is Model when it is published. It is compared in order to detect replacement of Model.
+	model *entities.Model
+	// now is simulated time when it is published
+	now time.Time
+	// player is serialized Player for each id
+	player map[uint]json.RawMessage
+	// scores is current statistics of Players
+	scores []*Score
+	// history is ScoreHistory when it is published. It is shared because ScoreHistory is only appended.
+	history []*Score
+}
+
+// published holds latest *View
+var published atomic.Value
+
+// muPublish is mutex lock for publishing View, because readers of Model may publish it at the same time.
+// It must be locked after MuModel.
+var muPublish sync.Mutex
+
+// delegateCache is serialized delegates in latest View. It is protected by muPublish.
+var delegateCache entities.DelegateCache
+
+// PublishView replaces View with current Model.
+// It must be called with lock of MuModel after Model changes.
+func PublishView() {
+	muPublish.Lock()
+	defer muPublish.Unlock()
+
+	v := &View{
+		PublishedAt: time.Now(),
+		model:       Model,
+		now:         GameClock.Now(),
+		player:      make(map[uint]json.RawMessage),
+		history:     ScoreHistory[:len(ScoreHistory):len(ScoreHistory)],
+	}
+	for _, o := range Model.Players {
+		data, err := json.Marshal(o)
+		if err != nil {
+			log.Printf("failed to publish %v: %v", o, err)
+			continue
+		}
+		v.player[o.ID] = data
+		v.scores = append(v.scores, newScore(o, v.now))
+	}
+	players, err := json.Marshal(v.player)
+	if err != nil {
+		log.Printf("failed to publish players: %v", err)
+		players = []byte("{}")
+	}
+	v.Players = players
+	v.Clock = GameClock.Status()
+	v.Routing = GetRoutingStatus()
+	v.Map, delegateCache = Model.CopyMap(delegateCache)
+	published.Store(v)
+}
+
+// initView discards View of previous Model.
+func initView() {
+	muPublish.Lock()
+	defer muPublish.Unlock()
+
+	published.Store((*View)(nil))
+	delegateCache = nil
+}
+
+// CurrentView returns latest View without lock of MuModel.
+// It publishes View first when nothing has been published yet.
+func CurrentView() *View {
+	if v, _ := published.Load().(*View); v != nil {
+		return v
+	}
+	MuModel.RLock()
+	defer MuModel.RUnlock()
+	PublishView()
+	return published.Load().(*View)
+}
@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+)
+
+// bulkInsert inserts rows with multi-row INSERT statements.
+// Rows are split so that the number of placeholders in a statement doesn't exceed maxVars.
+// suffix is appended to each statement for upsert.
+func bulkInsert(tx *gorm.DB, d ddl, table string, columns []string, rows [][]interface{}, suffix string, maxVars int) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	size := maxVars / len(columns)
+	if size < 1 {
+		size = 1
+	}
+	quoted := make([]string, len(columns))
+	for i, c := range columns {
+		quoted[i] = d.quote(c)
+	}
+	head := fmt.Sprintf("INSERT INTO %s (%s) VALUES ", d.quote(table), strings.Join(quoted, ", "))
+	placeholder := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ") + ")"
+
+	for i := 0; i < len(rows); i += size {
+		j := i + size
+		if j > len(rows) {
+			j = len(rows)
+		}
+		sql := head + strings.TrimSuffix(strings.Repeat(placeholder+", ", j-i), ", ") + suffix
+		args := make([]interface{}, 0, (j-i)*len(columns))
+		for _, row := range rows[i:j] {
+			if len(row) != len(columns) {
+				return fmt.Errorf("%s: row has %d values, want %d", table, len(row), len(columns))
+			}
+			args = append(args, row...)
+		}
+		if _, err := tx.CommonDB().Exec(sql, args...); err != nil {
+			return fmt.Errorf("%s: %v", table, err)
+		}
+	}
+	return nil
+}
+
+// updateColumns returns quoted columns except key.
+func updateColumns(d ddl, key string, columns []string) []string {
+	cols := []string{}
+	for _, c := range columns {
+		if c != key {
+			cols = append(cols, d.quote(c))
+		}
+	}
+	return cols
+}
@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// dialect is backend specific part of Storage.
+type dialect interface {
+	ddl
+	// maxVars is the number of placeholders backend accepts in a statement.
+	maxVars() int
+	// upsertSuffix returns clause appended to INSERT statement to update columns of existing key.
+	upsertSuffix(key string, columns []string) string
+	// dropColumn removes column from table. t is definition of table after removal.
+	dropColumn(db *gorm.DB, t Table, column string) error
+}
+
+// database is Storage on gorm connection.
+type database struct {
+	db *gorm.DB
+	dialect
+}
+
+func newDatabase(db *gorm.DB, d dialect) *database {
+	return &database{db, d}
+}
+
+// Begin starts transaction.
+func (s *database) Begin() (Tx, error) {
+	tx := s.db.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	return &transaction{tx, s.dialect}, nil
+}
+
+// Columns returns columns stored for obj and their values except specified ones.
+// Zero timestamps of created_at and updated_at are regarded as now as gorm does.
+func (s *database) Columns(obj interface{}, excepts ...string) ([]string, []interface{}) {
+	columns, values := []string{}, []interface{}{}
+	for _, f := range s.db.NewScope(obj).Fields() {
+		if !f.IsNormal || f.IsIgnored || contains(excepts, f.DBName) {
+			continue
+		}
+		v := f.Field.Interface()
+		if t, ok := v.(time.Time); ok && t.IsZero() && (f.DBName == "created_at" || f.DBName == "updated_at") {
+			v = time.Now()
+		}
+		columns = append(columns, f.DBName)
+		values = append(values, v)
+	}
+	return columns, values
+}
+
+// Max returns max value of unsigned integer column. 0 means no record.
+func (s *database) Max(table string, column string) (uint64, error) {
+	var max struct {
+		V uint64
+	}
+	sql := fmt.Sprintf("SELECT coalesce(max(%s), 0) as v FROM %s", s.quote(column), s.quote(table))
+	if err := s.db.Raw(sql).Scan(&max).Error; err != nil {
+		return 0, err
+	}
+	return max.V, nil
+}
+
+// Each scans records not deleted in table into object created by alloc and calls fn with it.
+func (s *database) Each(table string, alloc func() interface{}, fn func(obj interface{})) error {
+	rows, err := s.db.Table(table).Where("deleted_at is null").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		obj := alloc()
+		if err := s.db.ScanRows(rows, obj); err != nil {
+			return err
+		}
+		fn(obj)
+	}
+	return rows.Err()
+}
+
+// FindSince loads records whose column is since or later into out ordered by the column.
+func (s *database) FindSince(out interface{}, column string, since time.Time) error {
+	return s.db.Where(fmt.Sprintf("%s >= ?", s.quote(column)), since).Order(s.quote(column)).Find(out).Error
+}
+
+// HasTable reports whether table exists.
+func (s *database) HasTable(name string) bool {
+	return s.db.HasTable(name)
+}
+
+// HasColumn reports whether column exists in table.
+func (s *database) HasColumn(table string, column string) bool {
+	return s.db.Dialect().HasColumn(table, column)
+}
+
+// CreateTable creates table with its indexes and foreign keys.
+func (s *database) CreateTable(t Table) error {
+	return s.exec(createTableSQL(s, t))
+}
+
+// DropTable drops table if exists.
+func (s *database) DropTable(name string) error {
+	return s.db.Exec("DROP TABLE IF EXISTS " + s.quote(name)).Error
+}
+
+// AddColumn adds column to table.
+func (s *database) AddColumn(table string, c Column) error {
+	return s.exec(addColumnSQL(s, table, c))
+}
+
+// DropColumn removes column from table. t is definition of table after removal.
+func (s *database) DropColumn(t Table, column string) error {
+	return s.dropColumn(s.db, t, column)
+}
+
+// Close disconnects database.
+func (s *database) Close() error {
+	return s.db.Close()
+}
+
+func (s *database) exec(stmts []string) error {
+	for _, sql := range stmts {
+		if err := s.db.Exec(sql).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func contains(list []string, x string) bool {
+	for _, v := range list {
+		if v == x {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	// register mysql driver
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/jinzhu/gorm"
+)
+
+// mysqlRetry is how many times to try connecting to MySQL server.
+const mysqlRetry = 60
+
+// mysqlInterval is how long to wait MySQL server getting ready.
+const mysqlInterval = 10 * time.Second
+
+// mysqlMaxVars is the number of placeholders MySQL accepts in a statement.
+const mysqlMaxVars = 65535
+
+// mySQL is dialect of database on MySQL server.
+type mySQL struct{}
+
+// openMySQL connects to MySQL server.
+// It retries while server is starting up, as it is often launched with game server at the same time.
+func openMySQL(spec string) (Storage, error) {
+	var (
+		database *gorm.DB
+		err      error
+	)
+	for i := 1; i <= mysqlRetry; i++ {
+		if database, err = gorm.Open("mysql", spec); err == nil {
+			return newDatabase(database, mySQL{}), nil
+		}
+		if i < mysqlRetry {
+			log.Printf("failed to connect database(%v). retry after %v.", err, mysqlInterval)
+			time.Sleep(mysqlInterval)
+		}
+	}
+	return nil, err
+}
+
+func (mySQL) quote(name string) string {
+	return fmt.Sprintf("`%s`", name)
+}
+
+func (mySQL) columnType(c Column) string {
+	var typ string
+	switch c.Type {
+	case ID:
+		return "int unsigned AUTO_INCREMENT"
+	case Uint:
+		typ = "int unsigned"
+	case Int:
+		typ = "int"
+	case BigInt:
+		typ = "bigint"
+	case Float:
+		typ = "double"
+	case Bool:
+		typ = "boolean"
+	case String:
+		typ = "varchar(255)"
+	case Text:
+		typ = "text"
+	case Time:
+		// timestamp is NOT NULL without explicit NULL
+		typ = "timestamp"
+		if !c.NotNull {
+			typ += " NULL"
+		}
+	}
+	return constraints(typ, c)
+}
+
+func (mySQL) inlineKey() bool {
+	return false
+}
+
+func (mySQL) maxVars() int {
+	return mysqlMaxVars
+}
+
+func (d mySQL) upsertSuffix(key string, columns []string) string {
+	sets := []string{}
+	for _, c := range updateColumns(d, key, columns) {
+		sets = append(sets, fmt.Sprintf("%s = VALUES(%s)", c, c))
+	}
+	return " ON DUPLICATE KEY UPDATE " + strings.Join(sets, ", ")
+}
+
+func (d mySQL) dropColumn(db *gorm.DB, t Table, column string) error {
+	return db.Exec(fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", d.quote(t.Name), d.quote(column))).Error
+}
@@ -0,0 +1,154 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ColumnType is type of column independent from backend.
+type ColumnType int
+
+const (
+	// ID is auto increment primary key.
+	ID ColumnType = iota
+	// Uint is unsigned integer such as id of other table.
+	Uint
+	// Int is signed integer.
+	Int
+	// BigInt is 64 bit signed integer.
+	BigInt
+	// Float is double precision floating point number.
+	Float
+	// Bool is boolean.
+	Bool
+	// String is short string up to 255 characters.
+	String
+	// Text is long string.
+	Text
+	// Time is timestamp.
+	Time
+)
+
+// Column is definition of column.
+type Column struct {
+	Name    string
+	Type    ColumnType
+	NotNull bool
+	// Default is literal of default value. Empty means no default.
+	Default string
+	// Index creates index on this column.
+	Index bool
+}
+
+// ForeignKey is constraint that column refers id of other table.
+type ForeignKey struct {
+	Column   string
+	Table    string
+	OnDelete string
+	OnUpdate string
+}
+
+// Table is definition of table.
+type Table struct {
+	Name    string
+	Columns []Column
+	// PrimaryKey is column name of primary key. Column of ID type is used when it is empty.
+	PrimaryKey  string
+	ForeignKeys []ForeignKey
+}
+
+// primaryKey returns name of primary key column.
+func (t Table) primaryKey() string {
+	if t.PrimaryKey != "" {
+		return t.PrimaryKey
+	}
+	for _, c := range t.Columns {
+		if c.Type == ID {
+			return c.Name
+		}
+	}
+	return ""
+}
+
+// Column returns definition of specified column.
+func (t Table) Column(name string) (Column, bool) {
+	for _, c := range t.Columns {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return Column{}, false
+}
+
+// ddl is dialect specific part of statements to define tables.
+type ddl interface {
+	quote(name string) string
+	// columnType returns type and constraints of column.
+	columnType(c Column) string
+	// inlineKey reports whether primary key of ID type is declared in its column definition.
+	inlineKey() bool
+}
+
+// constraints appends NOT NULL and DEFAULT clause to type of column.
+func constraints(typ string, c Column) string {
+	if c.NotNull {
+		typ += " NOT NULL"
+	}
+	if c.Default != "" {
+		typ += " DEFAULT " + c.Default
+	}
+	return typ
+}
+
+// createTableSQL returns statements to create table and its indexes.
+// Foreign keys are declared in CREATE TABLE because SQLite can't add them afterward.
+func createTableSQL(d ddl, t Table) []string {
+	return append([]string{tableSQL(d, t, t.Name)}, indexSQL(d, t)...)
+}
+
+// tableSQL returns CREATE TABLE statement of t named as specified.
+func tableSQL(d ddl, t Table, name string) string {
+	defs := []string{}
+	for _, c := range t.Columns {
+		defs = append(defs, fmt.Sprintf("%s %s", d.quote(c.Name), d.columnType(c)))
+	}
+	if pk := t.primaryKey(); pk != "" {
+		if c, _ := t.Column(pk); c.Type != ID || !d.inlineKey() {
+			defs = append(defs, fmt.Sprintf("PRIMARY KEY (%s)", d.quote(pk)))
+		}
+	}
+	for _, fk := range t.ForeignKeys {
+		defs = append(defs, fmt.Sprintf("CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s(id) ON DELETE %s ON UPDATE %s",
+			d.quote(keyName("fk", t.Name, fk.Column)), d.quote(fk.Column), d.quote(fk.Table), fk.OnDelete, fk.OnUpdate))
+	}
+	return fmt.Sprintf("CREATE TABLE %s (%s)", d.quote(name), strings.Join(defs, ", "))
+}
+
+// indexSQL returns statements to create indexes of t.
+func indexSQL(d ddl, t Table) []string {
+	stmts := []string{}
+	for _, c := range t.Columns {
+		if c.Index {
+			stmts = append(stmts, createIndexSQL(d, t.Name, c.Name))
+		}
+	}
+	return stmts
+}
+
+// addColumnSQL returns statements to add column and its index.
+func addColumnSQL(d ddl, table string, c Column) []string {
+	stmts := []string{fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", d.quote(table), d.quote(c.Name), d.columnType(c))}
+	if c.Index {
+		stmts = append(stmts, createIndexSQL(d, table, c.Name))
+	}
+	return stmts
+}
+
+// createIndexSQL returns statement to create index named as gorm does.
+func createIndexSQL(d ddl, table string, column string) string {
+	return fmt.Sprintf("CREATE INDEX %s ON %s(%s)", d.quote(keyName("idx", table, column)), d.quote(table), d.quote(column))
+}
+
+func keyName(kind string, table string, column string) string {
+	return fmt.Sprintf("%s_%s_%s", kind, table, column)
+}
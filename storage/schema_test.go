@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCreateTableSQL(t *testing.T) {
+	table := Table{
+		Name: "children",
+		Columns: []Column{
+			{Name: "id", Type: ID},
+			{Name: "parent_id", Type: Uint, NotNull: true},
+			{Name: "at", Type: Time, Index: true},
+			{Name: "rate", Type: Float, NotNull: true, Default: "1"},
+		},
+		ForeignKeys: []ForeignKey{{Column: "parent_id", Table: "parents", OnDelete: "CASCADE", OnUpdate: "RESTRICT"}},
+	}
+	for _, c := range []struct {
+		name string
+		d    ddl
+		want []string
+	}{
+		{"mysql", mySQL{}, []string{
+			"CREATE TABLE `children` (`id` int unsigned AUTO_INCREMENT, `parent_id` int unsigned NOT NULL, " +
+				"`at` timestamp NULL, `rate` double NOT NULL DEFAULT 1, PRIMARY KEY (`id`), " +
+				"CONSTRAINT `fk_children_parent_id` FOREIGN KEY (`parent_id`) REFERENCES `parents`(id) ON DELETE CASCADE ON UPDATE RESTRICT)",
+			"CREATE INDEX `idx_children_at` ON `children`(`at`)",
+		}},
+		{"sqlite", sqLite{}, []string{
+			`CREATE TABLE "children" ("id" integer primary key autoincrement, "parent_id" integer NOT NULL, ` +
+				`"at" datetime, "rate" real NOT NULL DEFAULT 1, ` +
+				`CONSTRAINT "fk_children_parent_id" FOREIGN KEY ("parent_id") REFERENCES "parents"(id) ON DELETE CASCADE ON UPDATE RESTRICT)`,
+			`CREATE INDEX "idx_children_at" ON "children"("at")`,
+		}},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			if got := createTableSQL(c.d, table); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("createTableSQL() got\n%v\nwant\n%v", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+	// register sqlite3 driver
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+)
+
+// sqliteMaxVars is the number of placeholders SQLite accepts in a statement by default.
+const sqliteMaxVars = 999
+
+// sqLite is dialect of database on single file.
+// spec is path of the file or ":memory:".
+type sqLite struct{}
+
+// openSQLite opens database file.
+// Only one connection is used because SQLite locks whole file on writing
+// and each connection to ":memory:" has its own database.
+func openSQLite(spec string) (Storage, error) {
+	database, err := gorm.Open("sqlite3", spec)
+	if err != nil {
+		return nil, err
+	}
+	database.DB().SetMaxOpenConns(1)
+	if err := database.Exec("PRAGMA foreign_keys = ON").Error; err != nil {
+		database.Close()
+		return nil, err
+	}
+	return newDatabase(database, sqLite{}), nil
+}
+
+func (sqLite) quote(name string) string {
+	return fmt.Sprintf(`"%s"`, name)
+}
+
+func (sqLite) columnType(c Column) string {
+	var typ string
+	switch c.Type {
+	case ID:
+		return "integer primary key autoincrement"
+	case Uint, Int:
+		typ = "integer"
+	case BigInt:
+		typ = "bigint"
+	case Float:
+		typ = "real"
+	case Bool:
+		typ = "bool"
+	case String:
+		typ = "varchar(255)"
+	case Text:
+		typ = "text"
+	case Time:
+		typ = "datetime"
+	}
+	return constraints(typ, c)
+}
+
+func (sqLite) inlineKey() bool {
+	return true
+}
+
+func (sqLite) maxVars() int {
+	return sqliteMaxVars
+}
+
+func (d sqLite) upsertSuffix(key string, columns []string) string {
+	sets := []string{}
+	for _, c := range updateColumns(d, key, columns) {
+		sets = append(sets, fmt.Sprintf("%s = excluded.%s", c, c))
+	}
+	return fmt.Sprintf(" ON CONFLICT(%s) DO UPDATE SET %s", d.quote(key), strings.Join(sets, ", "))
+}
+
+// dropColumn rebuilds table because SQLite can't drop column.
+// Foreign keys are disabled while old table is replaced with new one.
+func (d sqLite) dropColumn(db *gorm.DB, t Table, column string) error {
+	if err := db.Exec("PRAGMA foreign_keys = OFF").Error; err != nil {
+		return err
+	}
+	defer db.Exec("PRAGMA foreign_keys = ON")
+
+	tmp := t.Name + "_new"
+	columns := []string{}
+	for _, c := range t.Columns {
+		columns = append(columns, d.quote(c.Name))
+	}
+	stmts := []string{
+		tableSQL(d, t, tmp),
+		fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s",
+			d.quote(tmp), strings.Join(columns, ", "), strings.Join(columns, ", "), d.quote(t.Name)),
+		"DROP TABLE " + d.quote(t.Name),
+		fmt.Sprintf("ALTER TABLE %s RENAME TO %s", d.quote(tmp), d.quote(t.Name)),
+	}
+	stmts = append(stmts, indexSQL(d, t)...)
+
+	tx := db.Begin()
+	for _, sql := range stmts {
+		if err := tx.Exec(sql).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit().Error
+}
@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+// Storage is database backend which persists Model.
+// Callers use only this interface so that they don't depend on query builder or SQL dialect.
+type Storage interface {
+	// Begin starts transaction.
+	Begin() (Tx, error)
+	// Columns returns columns stored for obj and their values except specified ones.
+	// Zero timestamps of created_at and updated_at are regarded as now.
+	Columns(obj interface{}, excepts ...string) ([]string, []interface{})
+	// Max returns max value of unsigned integer column. 0 means no record.
+	Max(table string, column string) (uint64, error)
+	// Each scans records not deleted in table into object created by alloc and calls fn with it.
+	Each(table string, alloc func() interface{}, fn func(obj interface{})) error
+	// FindSince loads records whose column is since or later into out ordered by the column.
+	// out is pointer to slice of struct pointer.
+	FindSince(out interface{}, column string, since time.Time) error
+
+	// HasTable reports whether table exists.
+	HasTable(name string) bool
+	// HasColumn reports whether column exists in table.
+	HasColumn(table string, column string) bool
+	// CreateTable creates table with its indexes and foreign keys.
+	CreateTable(t Table) error
+	// DropTable drops table if exists.
+	DropTable(name string) error
+	// AddColumn adds column to table.
+	AddColumn(table string, c Column) error
+	// DropColumn removes column from table. t is definition of table after removal.
+	DropColumn(t Table, column string) error
+
+	// Close disconnects database.
+	Close() error
+}
+
+// Tx is transaction. Changes are discarded unless Commit succeeds.
+type Tx interface {
+	// Insert inserts rows in bulk.
+	Insert(table string, columns []string, rows [][]interface{}) error
+	// Upsert inserts rows in bulk and updates other columns of rows whose key already exists.
+	Upsert(table string, key string, columns []string, rows [][]interface{}) error
+	// SoftDelete marks records of ids as deleted at specified time.
+	SoftDelete(table string, ids []uint, at time.Time) error
+	// Delete removes records whose column equals to value.
+	Delete(table string, column string, value interface{}) error
+	// DeleteAll removes all records of table.
+	DeleteAll(table string) error
+	// DeleteExcept removes records whose column doesn't equal to value.
+	DeleteExcept(table string, column string, value interface{}) error
+	// Fill sets value to column of all records.
+	Fill(table string, column string, value interface{}) error
+	// Commit applies changes.
+	Commit() error
+	// Rollback discards changes.
+	Rollback() error
+}
+
+// Open connects to database of specified driver.
+// driver is "mysql" or "sqlite3".
+func Open(driver string, spec string) (Storage, error) {
+	switch driver {
+	case "mysql":
+		return openMySQL(spec)
+	case "sqlite3":
+		return openSQLite(spec)
+	default:
+		return nil, fmt.Errorf("unsupported driver %s", driver)
+	}
+}
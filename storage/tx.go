@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// deleteChunk is max number of ids marked as deleted in a statement.
+const deleteChunk = 500
+
+// transaction is Tx on gorm connection.
+type transaction struct {
+	tx *gorm.DB
+	dialect
+}
+
+// Insert inserts rows in bulk.
+func (t *transaction) Insert(table string, columns []string, rows [][]interface{}) error {
+	return bulkInsert(t.tx, t, table, columns, rows, "", t.maxVars())
+}
+
+// Upsert inserts rows in bulk and updates other columns of rows whose key already exists.
+func (t *transaction) Upsert(table string, key string, columns []string, rows [][]interface{}) error {
+	return bulkInsert(t.tx, t, table, columns, rows, t.upsertSuffix(key, columns), t.maxVars())
+}
+
+// SoftDelete marks records of ids as deleted at specified time.
+func (t *transaction) SoftDelete(table string, ids []uint, at time.Time) error {
+	sql := fmt.Sprintf("UPDATE %s SET %s = ?, %s = ? WHERE %s IN (?)",
+		t.quote(table), t.quote("updated_at"), t.quote("deleted_at"), t.quote("id"))
+	for len(ids) > 0 {
+		n := len(ids)
+		if n > deleteChunk {
+			n = deleteChunk
+		}
+		if err := t.tx.Exec(sql, at, at, ids[:n]).Error; err != nil {
+			return fmt.Errorf("%s: %v", table, err)
+		}
+		ids = ids[n:]
+	}
+	return nil
+}
+
+// Delete removes records whose column equals to value.
+func (t *transaction) Delete(table string, column string, value interface{}) error {
+	return t.exec(fmt.Sprintf("DELETE FROM %s WHERE %s = ?", t.quote(table), t.quote(column)), value)
+}
+
+// DeleteAll removes all records of table.
+func (t *transaction) DeleteAll(table string) error {
+	return t.exec(fmt.Sprintf("DELETE FROM %s", t.quote(table)))
+}
+
+// DeleteExcept removes records whose column doesn't equal to value.
+func (t *transaction) DeleteExcept(table string, column string, value interface{}) error {
+	return t.exec(fmt.Sprintf("DELETE FROM %s WHERE %s <> ?", t.quote(table), t.quote(column)), value)
+}
+
+// Fill sets value to column of all records.
+func (t *transaction) Fill(table string, column string, value interface{}) error {
+	return t.exec(fmt.Sprintf("UPDATE %s SET %s = ?", t.quote(table), t.quote(column)), value)
+}
+
+// Commit applies changes.
+func (t *transaction) Commit() error {
+	return t.tx.Commit().Error
+}
+
+// Rollback discards changes.
+func (t *transaction) Rollback() error {
+	return t.tx.Rollback().Error
+}
+
+func (t *transaction) exec(sql string, args ...interface{}) error {
+	if err := t.tx.Exec(sql, args...).Error; err != nil {
+		return fmt.Errorf("%s: %v", sql, err)
+	}
+	return nil
+}